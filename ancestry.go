@@ -0,0 +1,153 @@
+package gitlog
+
+import (
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// MergeBaseParams for getting merge bases
+type MergeBaseParams struct {
+	All       bool // alias for `--all`
+	Octopus   bool // alias for `--octopus`
+	ForkPoint bool // alias for `--fork-point`, refs are `<ref> [<commit>]`
+}
+
+// validate check for incompatible combinations of options and the number of refs
+func (params *MergeBaseParams) validate(refs []string) error {
+	if params.ForkPoint {
+		if params.All || params.Octopus {
+			return &ParamsError{
+				Options: []string{"ForkPoint", "All", "Octopus"},
+				Reason:  "can not be used together",
+			}
+		}
+
+		if len(refs) < 1 || len(refs) > 2 {
+			return &ParamsError{
+				Options: []string{"ForkPoint"},
+				Reason:  "requires 1 or 2 refs",
+			}
+		}
+
+		return nil
+	}
+
+	if len(refs) < 2 {
+		return &ParamsError{
+			Options: []string{"MergeBase"},
+			Reason:  "requires at least 2 refs",
+		}
+	}
+
+	return nil
+}
+
+// MergeBase returns the best common ancestors of refs
+// an empty slice is returned if there is no common ancestor
+func (gitLog *Client) MergeBase(refs []string, params *MergeBaseParams) ([]*Commit, error) {
+	if params == nil {
+		params = &MergeBaseParams{}
+	}
+
+	if err := params.validate(refs); err != nil {
+		return nil, err
+	}
+
+	if err := validateRevisions(refs); err != nil {
+		return nil, err
+	}
+
+	if err := gitLog.prepare(); err != nil {
+		return nil, err
+	}
+
+	if _, err := gitLog.resolve(&RevSet{Include: refs}); err != nil {
+		return nil, err
+	}
+
+	args := []string{}
+
+	if params.All {
+		args = append(args, "--all")
+	}
+
+	if params.Octopus {
+		args = append(args, "--octopus")
+	}
+
+	if params.ForkPoint {
+		args = append(args, "--fork-point")
+	}
+
+	out, err := gitLog.exec("merge-base", append(args, refs...)...)
+	if exitStatus(err) == 1 {
+		return []*Commit{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	hashes := []string{}
+	for _, hash := range splitLines(out) {
+		hashes = appendUnique(hashes, hash)
+	}
+
+	tips, err := gitLog.tips(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := []*Commit{}
+	for _, hash := range hashes {
+		if commit, ok := tips[hash]; ok {
+			commits = append(commits, commit)
+		}
+	}
+
+	return commits, nil
+}
+
+// IsAncestor returns true if ancestor is reachable from descendant
+// a commit is an ancestor of itself
+func (gitLog *Client) IsAncestor(ancestor, descendant string) (bool, error) {
+	if err := validateRevisions([]string{ancestor, descendant}); err != nil {
+		return false, err
+	}
+
+	if err := gitLog.prepare(); err != nil {
+		return false, err
+	}
+
+	if _, err := gitLog.resolve(&RevSet{Include: []string{ancestor, descendant}}); err != nil {
+		return false, err
+	}
+
+	_, err := gitLog.exec("merge-base", "--is-ancestor", ancestor, descendant)
+	if exitStatus(err) == 1 {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// exitStatus returns the exit status of the git command, 0 if err is not exit error
+func exitStatus(err error) int {
+	if exitError, ok := err.(*exec.ExitError); ok {
+		if waitStatus, ok := exitError.Sys().(syscall.WaitStatus); ok {
+			return waitStatus.ExitStatus()
+		}
+	}
+	return 0
+}
+
+func splitLines(out string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
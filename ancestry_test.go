@@ -0,0 +1,94 @@
+package gitlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeBase(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setupBranches()
+	defer clear()
+
+	git("-C", ".tmp", "checkout", "--orphan", "orphan")
+	git("-C", ".tmp", "commit", "--allow-empty", "-m", "chore(*): Orphan commit")
+	git("-C", ".tmp", "checkout", "master")
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	commits, err := gitLog.MergeBase([]string{"master", "topic"}, nil)
+	assert.Nil(err)
+	assert.Equal(1, len(commits))
+	assert.Equal("docs(readme): Has body commit message", commits[0].Subject)
+	assert.Len(commits[0].Hash.Short, 7)
+
+	commits, err = gitLog.MergeBase([]string{"feature", "origin/master", "topic"}, &MergeBaseParams{Octopus: true})
+	assert.Nil(err)
+	assert.Equal(1, len(commits))
+	assert.Equal("docs(readme): Has body commit message", commits[0].Subject)
+
+	commits, err = gitLog.MergeBase([]string{"master", "feature"}, &MergeBaseParams{All: true})
+	assert.Nil(err)
+	assert.Equal(1, len(commits))
+	assert.Equal("chore(release): Bump version to v0.0.0", commits[0].Subject)
+
+	commits, err = gitLog.MergeBase([]string{"master", "feature"}, &MergeBaseParams{ForkPoint: true})
+	assert.Nil(err)
+	assert.Equal(1, len(commits))
+	assert.Equal("chore(release): Bump version to v0.0.0", commits[0].Subject)
+
+	commits, err = gitLog.MergeBase([]string{"master", "orphan"}, nil)
+	assert.Nil(err)
+	assert.Equal(0, len(commits))
+
+	_, err = gitLog.MergeBase([]string{"master"}, nil)
+	assert.IsType(&ParamsError{}, err)
+
+	_, err = gitLog.MergeBase([]string{"master", "topic"}, &MergeBaseParams{ForkPoint: true, All: true})
+	assert.IsType(&ParamsError{}, err)
+
+	_, err = gitLog.MergeBase([]string{"master", "--all"}, nil)
+	assert.IsType(&InvalidRevError{}, err)
+
+	_, err = gitLog.MergeBase([]string{"master", "notfound"}, nil)
+	assert.Equal(&RefError{Missing: []string{"notfound"}}, err)
+}
+
+func TestIsAncestor(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	table := []struct {
+		ancestor   string
+		descendant string
+		expect     bool
+	}{
+		{"v1.0.0", "2.1.0", true},
+		{"2.1.0", "v1.0.0", false},
+		{"topic", "master", true},
+		{"master", "topic", false},
+		{"master", "master", true},
+	}
+
+	for _, test := range table {
+		ok, err := gitLog.IsAncestor(test.ancestor, test.descendant)
+		assert.Nil(err)
+		assert.Equal(test.expect, ok, test.ancestor+" "+test.descendant)
+	}
+
+	_, err := gitLog.IsAncestor("notfound", "master")
+	assert.Equal(&RefError{Missing: []string{"notfound"}}, err)
+
+	_, err = gitLog.IsAncestor("master", "--output=/tmp/x")
+	assert.IsType(&InvalidRevError{}, err)
+}
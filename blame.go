@@ -0,0 +1,287 @@
+package gitlog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LineRange of file, End is inclusive and 0 means the end of file
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// BlameParams for getting git-blame
+type BlameParams struct {
+	Lines            []LineRange // alias for `-L <start>,<end>`
+	IgnoreWhitespace bool        // alias for `-w`
+	DetectMoves      bool        // alias for `-M`
+	DetectCopies     bool        // alias for `-C`
+	IgnoreRevsFile   string      // alias for `--ignore-revs-file <file>`, relative path is resolved from the repository
+}
+
+// BlameLine is a line of file
+type BlameLine struct {
+	OrigLine  int // line number in the original file of the commit
+	FinalLine int // line number in the blamed file
+	Text      string
+}
+
+// Blame is the contiguous lines attributed to the same commit
+type Blame struct {
+	Hash      *Hash
+	Author    *Author
+	Committer *Committer
+	Subject   string
+	Filename  string // filename in the commit
+	Boundary  bool
+	Lines     []*BlameLine
+}
+
+// zeroHash is used for the lines not committed yet
+const zeroHash = "0000000000000000000000000000000000000000"
+
+// Build command line args of git-blame
+func (gitLog *Client) buildBlameArgs(path, rev string, params *BlameParams) ([]string, error) {
+	args := []string{"--porcelain"}
+
+	if params != nil {
+		if params.IgnoreWhitespace {
+			args = append(args, "-w")
+		}
+
+		if params.DetectMoves {
+			args = append(args, "-M")
+		}
+
+		if params.DetectCopies {
+			args = append(args, "-C")
+		}
+
+		if params.IgnoreRevsFile != "" {
+			args = append(args, "--ignore-revs-file", params.IgnoreRevsFile)
+		}
+
+		for _, lines := range params.Lines {
+			if lines.Start < 1 || (lines.End != 0 && lines.End < lines.Start) {
+				return nil, fmt.Errorf("invalid line range %d,%d", lines.Start, lines.End)
+			}
+
+			if lines.End == 0 {
+				args = append(args, fmt.Sprintf("-L%d,", lines.Start))
+			} else {
+				args = append(args, fmt.Sprintf("-L%d,%d", lines.Start, lines.End))
+			}
+		}
+	}
+
+	if rev != "" {
+		if err := ValidateRevision(rev); err != nil {
+			return nil, err
+		}
+		args = append(args, rev)
+	}
+
+	return append(args, "--", path), nil
+}
+
+// Blame internally uses the git command to get the line attribution of the file
+// if rev is empty, the file in the working tree is blamed
+func (gitLog *Client) Blame(path, rev string, params *BlameParams) ([]*Blame, error) {
+	args, err := gitLog.buildBlameArgs(path, rev, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := gitLog.prepare(); err != nil {
+		return nil, err
+	}
+
+	// the output is not trimmed to keep the whitespace of the last line
+	out, err := gitLog.execRaw(nil, "blame", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	blames := parseBlame(out)
+
+	// Abbreviated hashes are decided by git
+	longs := []string{}
+	for _, blame := range blames {
+		if blame.Hash.Long != zeroHash {
+			longs = append(longs, blame.Hash.Long)
+		}
+	}
+
+	shorts, err := gitLog.shortHashes(longs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, blame := range blames {
+		if short, ok := shorts[blame.Hash.Long]; ok {
+			blame.Hash.Short = short
+		} else {
+			blame.Hash.Short = blame.Hash.Long[:7]
+		}
+	}
+
+	return blames, nil
+}
+
+// shortHashes returns the map of long hash to abbreviated hash
+func (gitLog *Client) shortHashes(longs []string) (map[string]string, error) {
+	shorts := map[string]string{}
+
+	unique := []string{}
+	for _, long := range longs {
+		if _, ok := shorts[long]; !ok {
+			shorts[long] = ""
+			unique = append(unique, long)
+		}
+	}
+
+	if len(unique) == 0 {
+		return shorts, nil
+	}
+
+	out, err := gitLog.exec("log", append([]string{"--no-walk=unsorted", "--format=%H %h"}, unique...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			shorts[fields[0]] = fields[1]
+		}
+	}
+
+	return shorts, nil
+}
+
+type blameCommit struct {
+	hash      *Hash
+	author    *Author
+	committer *Committer
+	subject   string
+	filename  string
+	boundary  bool
+}
+
+// parseBlame parses the output of `git blame --porcelain`
+func parseBlame(out string) []*Blame {
+	blames := []*Blame{}
+	commits := map[string]*blameCommit{}
+
+	var current *blameCommit
+	var blame *Blame
+	var line *BlameLine
+
+	for _, str := range strings.Split(out, "\n") {
+		// line content
+		if strings.HasPrefix(str, "\t") {
+			if line != nil {
+				line.Text = str[1:]
+				blame.Lines = append(blame.Lines, line)
+				line = nil
+			}
+			continue
+		}
+
+		parts := strings.SplitN(str, " ", 2)
+		key := parts[0]
+		value := ""
+		if len(parts) > 1 {
+			value = parts[1]
+		}
+
+		switch key {
+		case "author":
+			current.author.Name = value
+		case "author-mail":
+			current.author.Email = strings.Trim(value, "<>")
+		case "author-time":
+			current.author.Date = parseUnix(value)
+		case "author-tz":
+			current.author.Date = inZone(current.author.Date, value)
+		case "committer":
+			current.committer.Name = value
+		case "committer-mail":
+			current.committer.Email = strings.Trim(value, "<>")
+		case "committer-time":
+			current.committer.Date = parseUnix(value)
+		case "committer-tz":
+			current.committer.Date = inZone(current.committer.Date, value)
+		case "summary":
+			current.subject = value
+		case "boundary":
+			current.boundary = true
+		case "filename":
+			current.filename = value
+			if blame != nil && len(blame.Lines) == 0 {
+				blame.Filename = value
+			}
+		default:
+			if len(key) != len(zeroHash) {
+				continue
+			}
+
+			// <hash> <orig> <final> [<num_lines>]
+			fields := strings.Fields(value)
+			if len(fields) < 2 {
+				continue
+			}
+
+			current = commits[key]
+			if current == nil {
+				current = &blameCommit{
+					hash:      &Hash{Long: key},
+					author:    &Author{},
+					committer: &Committer{},
+				}
+				commits[key] = current
+			}
+
+			orig, _ := strconv.Atoi(fields[0])
+			final, _ := strconv.Atoi(fields[1])
+			line = &BlameLine{
+				OrigLine:  orig,
+				FinalLine: final,
+			}
+
+			// the first line of group has the number of lines
+			if len(fields) > 2 || blame == nil || blame.Hash != current.hash {
+				blame = &Blame{
+					Hash:     current.hash,
+					Filename: current.filename,
+				}
+				blames = append(blames, blame)
+			}
+		}
+	}
+
+	// commit details are given at the first occurrence only
+	for _, blame := range blames {
+		commit := commits[blame.Hash.Long]
+		blame.Author = commit.author
+		blame.Committer = commit.committer
+		blame.Subject = commit.subject
+		blame.Boundary = commit.boundary
+	}
+
+	return blames
+}
+
+// inZone returns t in the timezone of `+hhmm` or `-hhmm`, t is returned as it is if tz is malformed
+func inZone(t time.Time, tz string) time.Time {
+	zone, err := time.Parse("-0700", tz)
+	if err != nil {
+		return t
+	}
+
+	_, offset := zone.Zone()
+	return t.In(time.FixedZone("", offset))
+}
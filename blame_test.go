@@ -0,0 +1,229 @@
+package gitlog
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupBlame() func() {
+	clear := setup()
+
+	write := func(content string) {
+		ioutil.WriteFile(filepath.Join(".tmp", "file.txt"), []byte(content), 0644)
+	}
+
+	write("foo\nbar\nbaz\n")
+	git("-C", ".tmp", "add", "file.txt")
+	git("-C", ".tmp", "commit", "-m", "feat(file): Add file")
+
+	write("foo\nBAR\nbaz\nqux\n")
+	git("-C", ".tmp", "commit", "-am", "fix(file): Fix bar")
+
+	write("foo\n  BAR\nbaz\nqux\n\n")
+	git("-C", ".tmp", "commit", "-am", "style(file): Indent")
+
+	return clear
+}
+
+func TestParseBlame(t *testing.T) {
+	assert := assert.New(t)
+
+	out := `6a592952044f4ecc66fd9eef50b6d8414f7b8369 1 1 1
+author authorname
+author-mail <mail@example.com>
+author-time 1517138361
+author-tz +0900
+committer committername
+committer-mail <committer@example.com>
+committer-time 1517138362
+committer-tz +0900
+summary feat(file): Add file
+boundary
+filename old.txt
+	foo
+37f56a8448f58008620e204538f129f104f853fa 2 2 2
+author authorname
+author-mail <mail@example.com>
+author-time 1517138400
+author-tz +0900
+committer authorname
+committer-mail <mail@example.com>
+committer-time 1517138400
+committer-tz +0900
+summary fix(file): Fix bar
+previous 6a592952044f4ecc66fd9eef50b6d8414f7b8369 old.txt
+filename file.txt
+	BAR
+37f56a8448f58008620e204538f129f104f853fa 3 3
+	baz
+6a592952044f4ecc66fd9eef50b6d8414f7b8369 2 4 1
+	qux
+0000000000000000000000000000000000000000 5 5 1
+author Not Committed Yet
+author-mail <not.committed.yet>
+author-time 1517138500
+author-tz +0000
+committer Not Committed Yet
+committer-mail <not.committed.yet>
+committer-time 1517138500
+committer-tz +0000
+summary Version of file.txt from file.txt
+previous 37f56a8448f58008620e204538f129f104f853fa file.txt
+filename file.txt
+	`
+
+	blames := parseBlame(out)
+	assert.Equal(4, len(blames))
+
+	assert.Equal("6a592952044f4ecc66fd9eef50b6d8414f7b8369", blames[0].Hash.Long)
+	assert.Equal(&Author{Name: "authorname", Email: "mail@example.com", Date: time.Unix(1517138361, 0).In(time.FixedZone("", 9*60*60))}, blames[0].Author)
+	assert.Equal("2018-01-28 20:19:22 +0900", blames[0].Committer.Date.Format("2006-01-02 15:04:05 -0700"))
+	assert.Equal("committername", blames[0].Committer.Name)
+	assert.Equal("committer@example.com", blames[0].Committer.Email)
+	assert.Equal("feat(file): Add file", blames[0].Subject)
+	assert.Equal("old.txt", blames[0].Filename)
+	assert.True(blames[0].Boundary)
+	assert.Equal([]*BlameLine{{1, 1, "foo"}}, blames[0].Lines)
+
+	assert.Equal("fix(file): Fix bar", blames[1].Subject)
+	assert.Equal("file.txt", blames[1].Filename)
+	assert.False(blames[1].Boundary)
+	assert.Equal([]*BlameLine{{2, 2, "BAR"}, {3, 3, "baz"}}, blames[1].Lines)
+
+	assert.Equal(blames[0].Hash, blames[2].Hash)
+	assert.Equal("old.txt", blames[2].Filename)
+	assert.Equal("feat(file): Add file", blames[2].Subject)
+	assert.Equal([]*BlameLine{{2, 4, "qux"}}, blames[2].Lines)
+
+	assert.Equal(zeroHash, blames[3].Hash.Long)
+	assert.Equal("Not Committed Yet", blames[3].Author.Name)
+	assert.Equal([]*BlameLine{{5, 5, ""}}, blames[3].Lines)
+}
+
+func TestBlame(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setupBlame()
+	defer clear()
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	blames, err := gitLog.Blame("file.txt", "", nil)
+	assert.Nil(err)
+	assert.Equal(5, len(blames))
+
+	assert.Equal("feat(file): Add file", blames[0].Subject)
+	assert.Equal("authorname", blames[0].Author.Name)
+	assert.Equal("mail@example.com", blames[0].Committer.Email)
+	assert.Equal("file.txt", blames[0].Filename)
+	assert.False(blames[0].Boundary)
+	assert.Len(blames[0].Hash.Long, 40)
+	assert.Equal(blames[0].Hash.Long[:len(blames[0].Hash.Short)], blames[0].Hash.Short)
+	assert.Equal([]*BlameLine{{1, 1, "foo"}}, blames[0].Lines)
+
+	assert.Equal("style(file): Indent", blames[1].Subject)
+	assert.Equal([]*BlameLine{{2, 2, "  BAR"}}, blames[1].Lines)
+
+	assert.Equal(blames[0].Hash, blames[2].Hash)
+	assert.Equal([]*BlameLine{{3, 3, "baz"}}, blames[2].Lines)
+
+	assert.Equal("fix(file): Fix bar", blames[3].Subject)
+	assert.Equal([]*BlameLine{{4, 4, "qux"}}, blames[3].Lines)
+
+	assert.Equal(blames[1].Hash, blames[4].Hash)
+	assert.Equal([]*BlameLine{{5, 5, ""}}, blames[4].Lines)
+
+	// uncommitted lines
+	ioutil.WriteFile(filepath.Join(".tmp", "file.txt"), []byte("foo\nnew\n"), 0644)
+
+	blames, err = gitLog.Blame("file.txt", "", nil)
+	assert.Nil(err)
+	assert.Equal(2, len(blames))
+	assert.Equal(zeroHash, blames[1].Hash.Long)
+	assert.Equal("0000000", blames[1].Hash.Short)
+	assert.Equal([]*BlameLine{{2, 2, "new"}}, blames[1].Lines)
+
+	// committed file is not affected
+	blames, err = gitLog.Blame("file.txt", "HEAD", nil)
+	assert.Nil(err)
+	assert.Equal(5, len(blames))
+
+	// trailing whitespace of the last line
+	for _, content := range []string{"foo\nbar \t\n", "foo\nbar \t"} {
+		ioutil.WriteFile(filepath.Join(".tmp", "file.txt"), []byte(content), 0644)
+
+		blames, err = gitLog.Blame("file.txt", "", nil)
+		assert.Nil(err)
+		assert.Equal([]*BlameLine{{2, 2, "bar \t"}}, blames[1].Lines)
+	}
+}
+
+func blamesAt(blames []*Blame, final int) *Blame {
+	for _, blame := range blames {
+		for _, line := range blame.Lines {
+			if line.FinalLine == final {
+				return blame
+			}
+		}
+	}
+	return nil
+}
+
+func TestBlameParams(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setupBlame()
+	defer clear()
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	blames, err := gitLog.Blame("file.txt", "", &BlameParams{
+		IgnoreWhitespace: true,
+		Lines:            []LineRange{{Start: 2, End: 2}},
+	})
+	assert.Nil(err)
+	assert.Equal(1, len(blames))
+	assert.Equal("fix(file): Fix bar", blames[0].Subject)
+
+	blames, err = gitLog.Blame("file.txt", "HEAD~1", &BlameParams{
+		Lines: []LineRange{{Start: 3}},
+	})
+	assert.Nil(err)
+	assert.Equal("feat(file): Add file", blamesAt(blames, 3).Subject)
+	assert.Equal("fix(file): Fix bar", blamesAt(blames, 4).Subject)
+	assert.Nil(blamesAt(blames, 2))
+
+	args, _ := gitLog.buildBlameArgs("file.txt", "v1.0.0", &BlameParams{
+		IgnoreWhitespace: true,
+		DetectMoves:      true,
+		DetectCopies:     true,
+		IgnoreRevsFile:   ".git-blame-ignore-revs",
+		Lines:            []LineRange{{1, 10}, {20, 0}},
+	})
+	assert.Equal([]string{
+		"--porcelain", "-w", "-M", "-C",
+		"--ignore-revs-file", ".git-blame-ignore-revs",
+		"-L1,10", "-L20,",
+		"v1.0.0", "--", "file.txt",
+	}, args)
+
+	_, err = gitLog.Blame("file.txt", "", &BlameParams{Lines: []LineRange{{Start: 0}}})
+	assert.NotNil(err)
+
+	_, err = gitLog.Blame("file.txt", "", &BlameParams{Lines: []LineRange{{Start: 3, End: 2}}})
+	assert.NotNil(err)
+
+	_, err = gitLog.Blame("file.txt", "--output=/tmp/x", nil)
+	assert.IsType(&InvalidRevError{}, err)
+
+	_, err = gitLog.Blame("notfound.txt", "", nil)
+	assert.NotNil(err)
+}
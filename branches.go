@@ -0,0 +1,210 @@
+package gitlog
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const branchesFormat = separator +
+	"%(refname)" + delimiter +
+	"%(objectname)" + delimiter +
+	"%(upstream:short)" + delimiter +
+	"%(HEAD)" + delimiter +
+	"%(symref)"
+
+// BranchType of branch
+type BranchType int
+
+// List of BranchType
+const (
+	BranchLocal  BranchType = iota + 1 // refs/heads
+	BranchRemote                       // refs/remotes
+)
+
+// BranchFilter filters branches by BranchType
+type BranchFilter int
+
+// List of BranchFilter
+const (
+	BranchFilterAll BranchFilter = iota
+	BranchFilterLocal
+	BranchFilterRemote
+)
+
+// BranchesParams for getting branches
+type BranchesParams struct {
+	Base    string // base ref of ahead/behind counts, default "HEAD"
+	Pattern string // glob pattern of branch name (e.g. `feature/*`, `origin/*`)
+	Filter  BranchFilter
+}
+
+// Branch is a local or remote-tracking branch
+type Branch struct {
+	Name     string // e.g. `master`, `origin/master`
+	Ref      string // e.g. `refs/heads/master`, `refs/remotes/origin/master`
+	Type     BranchType
+	Current  bool      // true if HEAD points to the branch
+	Commit   *Commit   // tip commit
+	Upstream string    // e.g. `origin/master`, empty if no upstream is set
+	Date     time.Time // committer date of the tip commit
+	Base     string    // base ref of Ahead and Behind
+	Ahead    int       // number of commits not in Base
+	Behind   int       // number of commits of Base not in the branch
+}
+
+// Unique returns RevArgs of the commits not in Base (`<base>..<branch>`)
+func (branch *Branch) Unique() RevArgs {
+	return &RevRange{
+		Old: branch.Base,
+		New: branch.Ref,
+	}
+}
+
+// Branches returns the branches sorted by name with the ahead/behind counts against the base
+// symbolic refs such as `origin/HEAD` are omitted
+func (gitLog *Client) Branches(params *BranchesParams) ([]*Branch, error) {
+	if params == nil {
+		params = &BranchesParams{}
+	}
+
+	base := orHead(params.Base)
+	if err := ValidateRevision(base); err != nil {
+		return nil, err
+	}
+
+	// Check pattern before running git
+	if _, err := path.Match(params.Pattern, ""); err != nil {
+		return nil, err
+	}
+
+	if err := gitLog.prepare(); err != nil {
+		return nil, err
+	}
+
+	refs := []string{}
+	if params.Filter != BranchFilterRemote {
+		refs = append(refs, "refs/heads")
+	}
+	if params.Filter != BranchFilterLocal {
+		refs = append(refs, "refs/remotes")
+	}
+
+	out, err := gitLog.exec("for-each-ref", append([]string{"--format=" + branchesFormat}, refs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := []*Branch{}
+	tips := []string{}
+
+	for _, record := range strings.Split(out, separator)[1:] {
+		fields := strings.Split(record, delimiter)
+		for len(fields) < 5 {
+			fields = append(fields, "")
+		}
+
+		if strings.TrimSpace(fields[4]) != "" {
+			continue
+		}
+
+		branch := &Branch{
+			Ref:      fields[0],
+			Current:  fields[3] == "*",
+			Upstream: fields[2],
+			Base:     base,
+			Commit: &Commit{
+				Hash: &Hash{Long: fields[1]},
+			},
+		}
+
+		if strings.HasPrefix(branch.Ref, "refs/heads/") {
+			branch.Type = BranchLocal
+			branch.Name = strings.TrimPrefix(branch.Ref, "refs/heads/")
+		} else {
+			branch.Type = BranchRemote
+			branch.Name = strings.TrimPrefix(branch.Ref, "refs/remotes/")
+		}
+
+		if params.Pattern != "" {
+			if ok, _ := path.Match(params.Pattern, branch.Name); !ok {
+				continue
+			}
+		}
+
+		branches = append(branches, branch)
+		tips = appendUnique(tips, fields[1])
+	}
+
+	if len(branches) == 0 {
+		return branches, nil
+	}
+
+	commits, err := gitLog.tips(tips)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, branch := range branches {
+		if commit, ok := commits[branch.Commit.Hash.Long]; ok {
+			branch.Commit = commit
+			branch.Date = commit.Committer.Date
+		}
+
+		branch.Behind, branch.Ahead, err = gitLog.aheadBehind(base, branch.Ref)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return branches, nil
+}
+
+// tips returns the map of hash to Commit without walking the history
+func (gitLog *Client) tips(hashes []string) (map[string]*Commit, error) {
+	args := append([]string{"--no-walk=unsorted"}, gitLog.buildArgs(&RevSet{Include: hashes}, nil, false)...)
+
+	out, err := gitLog.exec("log", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := gitLog.parser.parse(&out)
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[string]*Commit{}
+	for _, commit := range commits {
+		results[commit.Hash.Long] = commit
+	}
+
+	return results, nil
+}
+
+// aheadBehind returns the number of commits only in left and only in right
+func (gitLog *Client) aheadBehind(left, right string) (int, int, error) {
+	out, err := gitLog.exec("rev-list", "--left-right", "--count", left+"..."+right, "--")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected output of rev-list \"%s\"", out)
+	}
+
+	l, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	r, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return l, r, nil
+}
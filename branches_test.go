@@ -0,0 +1,124 @@
+package gitlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupBranches() func() {
+	clear := setup()
+
+	git("-C", ".tmp", "checkout", "-b", "feature")
+	git("-C", ".tmp", "commit", "--allow-empty", "-m", "feat(branch): Add foo")
+	git("-C", ".tmp", "commit", "--allow-empty", "-m", "feat(branch): Add bar")
+	git("-C", ".tmp", "checkout", "master")
+
+	git("-C", ".tmp", "update-ref", "refs/remotes/origin/master", "master~1")
+	git("-C", ".tmp", "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/master")
+	git("-C", ".tmp", "remote", "add", "origin", "https://example.com/repo.git")
+	git("-C", ".tmp", "config", "branch.master.remote", "origin")
+	git("-C", ".tmp", "config", "branch.master.merge", "refs/heads/master")
+
+	return clear
+}
+
+func branchNames(branches []*Branch) []string {
+	names := []string{}
+	for _, branch := range branches {
+		names = append(names, branch.Name)
+	}
+	return names
+}
+
+func TestBranches(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setupBranches()
+	defer clear()
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	branches, err := gitLog.Branches(nil)
+	assert.Nil(err)
+	assert.Equal([]string{"feature", "master", "topic", "origin/master"}, branchNames(branches))
+
+	feature := branches[0]
+	assert.Equal("refs/heads/feature", feature.Ref)
+	assert.Equal(BranchLocal, feature.Type)
+	assert.False(feature.Current)
+	assert.Equal("", feature.Upstream)
+	assert.Equal("feat(branch): Add bar", feature.Commit.Subject)
+	assert.Equal(feature.Commit.Committer.Date, feature.Date)
+	assert.Equal("HEAD", feature.Base)
+	assert.Equal(2, feature.Ahead)
+	assert.Equal(0, feature.Behind)
+
+	master := branches[1]
+	assert.True(master.Current)
+	assert.Equal("origin/master", master.Upstream)
+	assert.Equal("chore(release): Bump version to v0.0.0", master.Commit.Subject)
+	assert.Len(master.Commit.Hash.Short, 7)
+	assert.Equal(0, master.Ahead)
+	assert.Equal(0, master.Behind)
+
+	topic := branches[2]
+	assert.Equal(0, topic.Ahead)
+	assert.Equal(5, topic.Behind)
+
+	remote := branches[3]
+	assert.Equal("refs/remotes/origin/master", remote.Ref)
+	assert.Equal(BranchRemote, remote.Type)
+	assert.Equal("style(*): Run GoFmt", remote.Commit.Subject)
+	assert.Equal(0, remote.Ahead)
+	assert.Equal(1, remote.Behind)
+
+	commits, err := gitLog.Log(feature.Unique(), nil)
+	assert.Nil(err)
+	assert.Equal(2, len(commits))
+	assert.Equal("feat(branch): Add bar", commits[0].Subject)
+	assert.Equal("feat(branch): Add foo", commits[1].Subject)
+}
+
+func TestBranchesParams(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setupBranches()
+	defer clear()
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	branches, err := gitLog.Branches(&BranchesParams{
+		Base:   "feature",
+		Filter: BranchFilterLocal,
+	})
+	assert.Nil(err)
+	assert.Equal([]string{"feature", "master", "topic"}, branchNames(branches))
+	assert.Equal(0, branches[1].Ahead)
+	assert.Equal(2, branches[1].Behind)
+
+	commits, err := gitLog.Log(branches[1].Unique(), nil)
+	assert.Nil(err)
+	assert.Equal(0, len(commits))
+
+	branches, err = gitLog.Branches(&BranchesParams{Filter: BranchFilterRemote})
+	assert.Nil(err)
+	assert.Equal([]string{"origin/master"}, branchNames(branches))
+
+	branches, err = gitLog.Branches(&BranchesParams{Pattern: "ma*"})
+	assert.Nil(err)
+	assert.Equal([]string{"master"}, branchNames(branches))
+
+	_, err = gitLog.Branches(&BranchesParams{Pattern: "["})
+	assert.NotNil(err)
+
+	_, err = gitLog.Branches(&BranchesParams{Base: "--all"})
+	assert.IsType(&InvalidRevError{}, err)
+
+	_, err = gitLog.Branches(&BranchesParams{Base: "notfound"})
+	assert.NotNil(err)
+}
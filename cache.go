@@ -0,0 +1,555 @@
+package gitlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	cacheCommitsFile = "commits.ndjson"
+	cacheRefsFile    = "refs.json"
+
+	// cacheWalks is the number of the walks kept by Cache, the oldest is dropped first
+	cacheWalks = 32
+)
+
+// Cache stores the parsed commits on disk keyed by object ID
+// commits are immutable, so only the commits reachable from new ref tips are fetched on Update.
+// when a ref is rewritten (e.g. force push), the commits no longer reachable from any ref are invalidated.
+type Cache struct {
+	gitLog  *Client
+	dir     string
+	mu      sync.Mutex
+	commits map[string]*Commit
+	refs    map[string]string   // ref to object ID at the last update
+	hashes  map[string][]string // hashes of git-log keyed by the args, valid while the refs are not changed
+	walks   []string            // keys of hashes, oldest first
+}
+
+// cacheRefs is the content of refs.json
+type cacheRefs struct {
+	Refs map[string]string `json:"refs"`
+}
+
+// CacheStats is the result of Update
+type CacheStats struct {
+	Fetched     int  // number of fetched commits
+	Invalidated int  // number of invalidated commits
+	Rewritten   bool // true if a ref is rewritten
+}
+
+// NewCache returns Cache stored in dir, existing cache in dir is loaded
+func NewCache(config *Config, dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	cache := &Cache{
+		gitLog:  NewClient(config),
+		dir:     dir,
+		commits: map[string]*Commit{},
+		refs:    map[string]string{},
+		hashes:  map[string][]string{},
+	}
+
+	if err := cache.load(); err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+func (cache *Cache) load() error {
+	filename := filepath.Join(cache.dir, cacheCommitsFile)
+
+	content, err := ioutil.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// every record ends with a newline, the rest is written by an interrupted append
+	if end := bytes.LastIndexByte(content, '\n') + 1; end < len(content) {
+		if err := os.Truncate(filename, int64(end)); err != nil {
+			return err
+		}
+		content = content[:end]
+	}
+
+	decoder := NewNDJSONDecoder(bytes.NewReader(content))
+	for {
+		commit, err := decoder.Decode()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		// same location as the commits of Log
+		commit.Author.Date = commit.Author.Date.Local()
+		commit.Committer.Date = commit.Committer.Date.Local()
+		commit.Tag.Date = commit.Author.Date
+
+		cache.commits[commit.Hash.Long] = commit
+	}
+
+	content, err = ioutil.ReadFile(filepath.Join(cache.dir, cacheRefsFile))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err == nil {
+		refs := &cacheRefs{}
+		if err := json.Unmarshal(content, refs); err != nil {
+			return err
+		}
+
+		if refs.Refs != nil {
+			cache.refs = refs.Refs
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of cached commits
+func (cache *Cache) Len() int {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return len(cache.commits)
+}
+
+// Update fetches the commits reachable from new ref tips, and invalidates the commits of rewritten history
+func (cache *Cache) Update() (*CacheStats, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if err := cache.gitLog.prepare(); err != nil {
+		return nil, err
+	}
+
+	return cache.update()
+}
+
+func (cache *Cache) update() (*CacheStats, error) {
+	stats := &CacheStats{}
+
+	refs, err := cache.currentRefs()
+	if err != nil {
+		return nil, err
+	}
+
+	tips := []string{}
+	known := []string{}
+	retag := []string{}
+
+	for _, id := range refs {
+		tips = appendUnique(tips, id)
+	}
+
+	for ref, old := range cache.refs {
+		id, ok := refs[ref]
+
+		switch {
+		case !ok:
+			// deleted refs may leave unreachable commits
+			stats.Rewritten = true
+		case id == old:
+			known = appendUnique(known, old)
+		case cache.isAncestor(old, id):
+			known = appendUnique(known, old)
+		default:
+			stats.Rewritten = true
+		}
+
+		// decorations of the commits are changed
+		if strings.HasPrefix(ref, "refs/tags/") && id != old {
+			retag = append(retag, old, id)
+		}
+	}
+
+	for ref, id := range refs {
+		if _, ok := cache.refs[ref]; !ok && strings.HasPrefix(ref, "refs/tags/") {
+			retag = append(retag, id)
+		}
+	}
+
+	fetched := []*Commit{}
+
+	if len(tips) > 0 {
+		revs := append([]string{}, tips...)
+		for _, id := range known {
+			revs = append(revs, "^"+id)
+		}
+
+		commits, err := cache.fetch(revs, false)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, commit := range commits {
+			if _, ok := cache.commits[commit.Hash.Long]; !ok {
+				fetched = append(fetched, commit)
+			}
+		}
+	}
+
+	// refetch the commits whose tags are changed
+	stale := []string{}
+	for _, id := range retag {
+		if _, ok := cache.commits[id]; ok {
+			stale = appendUnique(stale, id)
+		}
+	}
+
+	refetched, err := cache.fetchHashes(stale)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, commit := range append(fetched, refetched...) {
+		cache.commits[commit.Hash.Long] = commit
+	}
+
+	stats.Fetched = len(fetched)
+
+	if stats.Rewritten {
+		stats.Invalidated, err = cache.invalidate(tips)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !sameRefs(cache.refs, refs) {
+		cache.hashes = map[string][]string{}
+		cache.walks = nil
+	}
+
+	cache.refs = refs
+
+	if stats.Invalidated > 0 || len(refetched) > 0 {
+		err = cache.save()
+	} else {
+		err = cache.append(fetched)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, cache.saveRefs()
+}
+
+// currentRefs returns the refs and HEAD, annotated tags are peeled
+func (cache *Cache) currentRefs() (map[string]string, error) {
+	refs := map[string]string{}
+
+	out, err := cache.gitLog.exec("for-each-ref", "--format=%(refname) %(objectname) %(*objectname)", "refs/")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range splitLines(out) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		id := fields[1]
+		if len(fields) > 2 {
+			id = fields[2]
+		}
+
+		refs[fields[0]] = id
+	}
+
+	// HEAD does not exist in the empty repository
+	if head, err := cache.gitLog.exec("rev-parse", "--verify", "--quiet", "HEAD^{commit}"); err == nil && head != "" {
+		refs["HEAD"] = head
+	}
+
+	return refs, nil
+}
+
+func sameRefs(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for ref, id := range a {
+		if b[ref] != id {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (cache *Cache) isAncestor(ancestor, descendant string) bool {
+	_, err := cache.gitLog.exec("merge-base", "--is-ancestor", ancestor, descendant)
+	return err == nil
+}
+
+// invalidate removes the commits not reachable from tips
+func (cache *Cache) invalidate(tips []string) (int, error) {
+	reachable := map[string]bool{}
+
+	if len(tips) > 0 {
+		out, err := cache.gitLog.execRaw(revsReader(tips), "rev-list", "--stdin", "--")
+		if err != nil {
+			return 0, err
+		}
+
+		for _, hash := range splitLines(out) {
+			reachable[hash] = true
+		}
+	}
+
+	count := 0
+	for hash := range cache.commits {
+		if !reachable[hash] {
+			delete(cache.commits, hash)
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// revsReader returns the revisions for `--stdin`, so the number of revisions is not limited by the command line
+func revsReader(revs []string) io.Reader {
+	return strings.NewReader(strings.Join(revs, "\n") + "\n")
+}
+
+// fetch runs git-log of revs without cache
+func (cache *Cache) fetch(revs []string, noWalk bool) ([]*Commit, error) {
+	args := cache.gitLog.buildArgs(nil, nil, false)
+
+	// `--stdin` is placed before `--`
+	args = append(args[:len(args)-1], "--stdin", "--")
+
+	if noWalk {
+		args = append([]string{"--no-walk=unsorted"}, args...)
+	}
+
+	out, err := cache.gitLog.execRaw(revsReader(revs), "log", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := cache.gitLog.parser.parse(&out)
+	if err != nil {
+		return nil, err
+	}
+
+	// revs of the cache never have the sides
+	clearSides(commits)
+
+	return commits, nil
+}
+
+// fetchHashes fetches the commits of hashes
+func (cache *Cache) fetchHashes(hashes []string) ([]*Commit, error) {
+	if len(hashes) == 0 {
+		return []*Commit{}, nil
+	}
+
+	return cache.fetch(hashes, true)
+}
+
+// append writes the commits to the end of the file
+func (cache *Cache) append(commits []*Commit) error {
+	if len(commits) == 0 {
+		return nil
+	}
+
+	file, err := os.OpenFile(filepath.Join(cache.dir, cacheCommitsFile), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	if err := EncodeNDJSON(file, commits); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// save rewrites the whole file
+func (cache *Cache) save() error {
+	commits := make([]*Commit, 0, len(cache.commits))
+	for _, commit := range cache.commits {
+		commits = append(commits, commit)
+	}
+
+	return writeFileAtomic(filepath.Join(cache.dir, cacheCommitsFile), func(w io.Writer) error {
+		return EncodeNDJSON(w, commits)
+	})
+}
+
+func (cache *Cache) saveRefs() error {
+	return writeFileAtomic(filepath.Join(cache.dir, cacheRefsFile), func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(&cacheRefs{Refs: cache.refs})
+	})
+}
+
+// writeFileAtomic writes to the temporary file and renames it
+func writeFileAtomic(filename string, write func(io.Writer) error) error {
+	file, err := ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename))
+	if err != nil {
+		return err
+	}
+
+	if err := write(file); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	return os.Rename(file.Name(), filename)
+}
+
+// Log returns the same result as GitLog.Log using the cache
+// Params.Mailmap, Params.Boundary and RevSymmetric are not cached, git-log is executed directly.
+// the hashes of the last 32 queries are reused while the refs are not changed, except for reflog such as `@{1.week.ago}`.
+func (cache *Cache) Log(rev RevArgs, params *Params) ([]*Commit, error) {
+	if !cacheable(rev, params) {
+		return cache.gitLog.Log(rev, params)
+	}
+
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	if validator, ok := rev.(RevValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if err := cache.gitLog.prepare(); err != nil {
+		return nil, err
+	}
+
+	if params != nil && params.Verify {
+		resolved, err := cache.gitLog.resolve(rev)
+		if err != nil {
+			return nil, err
+		}
+		rev = resolved
+	}
+
+	if _, err := cache.update(); err != nil {
+		return nil, err
+	}
+
+	hashes, err := cache.walk(rev, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// e.g. the commits not reachable from refs
+	missing := []string{}
+	for _, hash := range hashes {
+		if _, ok := cache.commits[hash]; !ok {
+			missing = append(missing, hash)
+		}
+	}
+
+	fetched, err := cache.fetchHashes(missing)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fetched) > 0 {
+		for _, commit := range fetched {
+			cache.commits[commit.Hash.Long] = commit
+		}
+
+		if err := cache.append(fetched); err != nil {
+			return nil, err
+		}
+	}
+
+	commits := []*Commit{}
+	for _, hash := range hashes {
+		if commit, ok := cache.commits[hash]; ok {
+			commits = append(commits, copyCommit(commit))
+		}
+	}
+
+	return commits, nil
+}
+
+// walk returns the hashes of git-log, the last cacheWalks results are kept until the refs are changed
+func (cache *Cache) walk(rev RevArgs, params *Params) ([]string, error) {
+	revArgs := cache.gitLog.buildRevArgs(rev, params)
+	key := strings.Join(revArgs, "\x00")
+
+	if hashes, ok := cache.hashes[key]; ok {
+		return hashes, nil
+	}
+
+	// same revisions as git-log, but only the hashes
+	out, err := cache.gitLog.exec("log", append([]string{"--no-decorate", "--format=%H"}, revArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := splitLines(out)
+
+	// reflog is changed without updating the refs
+	if !strings.Contains(key, "@{") {
+		if len(cache.walks) >= cacheWalks {
+			delete(cache.hashes, cache.walks[0])
+			cache.walks = cache.walks[1:]
+		}
+		cache.hashes[key] = hashes
+		cache.walks = append(cache.walks, key)
+	}
+
+	return hashes, nil
+}
+
+// cacheable returns false if the result depends on the query
+func cacheable(rev RevArgs, params *Params) bool {
+	if params != nil && (params.Mailmap || params.Boundary) {
+		return false
+	}
+
+	if sides, ok := rev.(revSides); ok && len(sides.sideArgs()) > 0 {
+		return false
+	}
+
+	return true
+}
+
+// copyCommit copies the commit, so that the cached commit is not modified by the caller
+func copyCommit(commit *Commit) *Commit {
+	c := *commit
+
+	hash := *commit.Hash
+	tree := *commit.Tree
+	author := *commit.Author
+	committer := *commit.Committer
+	tag := *commit.Tag
+
+	c.Hash, c.Tree, c.Author, c.Committer, c.Tag = &hash, &tree, &author, &committer, &tag
+
+	return &c
+}
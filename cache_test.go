@@ -0,0 +1,207 @@
+package gitlog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func subjects(commits []*Commit) []string {
+	list := []string{}
+	for _, commit := range commits {
+		list = append(list, commit.Subject)
+	}
+	return list
+}
+
+func TestCache(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	dir, err := ioutil.TempDir("", "gitlog-cache")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	config := &Config{
+		Path: ".tmp",
+	}
+
+	cache, err := NewCache(config, dir)
+	assert.Nil(err)
+
+	stats, err := cache.Update()
+	assert.Nil(err)
+	assert.Equal(&CacheStats{Fetched: 7}, stats)
+	assert.Equal(7, cache.Len())
+
+	stats, err = cache.Update()
+	assert.Nil(err)
+	assert.Equal(&CacheStats{}, stats)
+
+	// same as Log
+	expected, _ := New(config).Log(&RevRange{Old: "v1.0.0", New: "HEAD"}, &Params{Reverse: true})
+	commits, err := cache.Log(&RevRange{Old: "v1.0.0", New: "HEAD"}, &Params{Reverse: true})
+	assert.Nil(err)
+	assert.Equal(subjects(expected), subjects(commits))
+	assert.Equal("2.1.0", commits[2].Tag.Name)
+	assert.Equal(expected[2].Author.Date.Unix(), commits[2].Author.Date.Unix())
+
+	// the walk is reused while the refs are not changed
+	assert.Equal(1, len(cache.hashes))
+	commits, err = cache.Log(&RevRange{Old: "v1.0.0", New: "HEAD"}, &Params{Reverse: true})
+	assert.Nil(err)
+	assert.Equal(subjects(expected), subjects(commits))
+	assert.Equal(1, len(cache.hashes))
+
+	// the walks are bounded
+	for i := 1; i <= cacheWalks; i++ {
+		_, err = cache.Log(&RevNumber{i}, nil)
+		assert.Nil(err)
+	}
+	assert.Equal(cacheWalks, len(cache.hashes))
+	assert.Equal(cacheWalks, len(cache.walks))
+	assert.Equal(cache.walks[0], strings.Join(cache.gitLog.buildRevArgs(&RevNumber{1}, nil), "\x00"))
+
+	// returned commits are copies
+	commits[0].Subject = "modified"
+	commits, _ = cache.Log(&RevNumber{1}, nil)
+	assert.Equal("chore(release): Bump version to v0.0.0", commits[0].Subject)
+
+	// new commits
+	git("-C", ".tmp", "commit", "--allow-empty", "-m", "feat(cache): Add cache")
+	git("-C", ".tmp", "tag", "v4.0.0")
+
+	commits, err = cache.Log(&RevNumber{2}, nil)
+	assert.Nil(err)
+	assert.Equal([]string{"feat(cache): Add cache", "chore(release): Bump version to v0.0.0"}, subjects(commits))
+	assert.Equal("v4.0.0", commits[0].Tag.Name)
+	assert.Equal(8, cache.Len())
+
+	// new commits reset the walks
+	assert.Equal(1, len(cache.hashes))
+
+	// loaded from disk
+	cache, err = NewCache(config, dir)
+	assert.Nil(err)
+	assert.Equal(8, cache.Len())
+
+	// partial record of an interrupted append is dropped
+	filename := filepath.Join(dir, cacheCommitsFile)
+	file, _ := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND, 0644)
+	file.WriteString(`{"hash":{"long":"`)
+	file.Close()
+
+	cache, err = NewCache(config, dir)
+	assert.Nil(err)
+	assert.Equal(8, cache.Len())
+
+	content, _ := ioutil.ReadFile(filename)
+	assert.True(strings.HasSuffix(string(content), "}\n"))
+
+	stats, err = cache.Update()
+	assert.Nil(err)
+	assert.Equal(&CacheStats{}, stats)
+
+	// moved tag
+	git("-C", ".tmp", "tag", "-f", "v4.0.0", "HEAD~1")
+
+	commits, err = cache.Log(&RevNumber{2}, nil)
+	assert.Nil(err)
+	assert.Equal("", commits[0].Tag.Name)
+	assert.Equal("v4.0.0", commits[1].Tag.Name)
+}
+
+func TestCacheRewrite(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	dir, err := ioutil.TempDir("", "gitlog-cache")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	git("-C", ".tmp", "checkout", "-b", "feature")
+	git("-C", ".tmp", "commit", "--allow-empty", "-m", "feat(a): Add a")
+	git("-C", ".tmp", "commit", "--allow-empty", "-m", "feat(b): Add b")
+
+	cache, err := NewCache(&Config{Path: ".tmp"}, dir)
+	assert.Nil(err)
+
+	stats, err := cache.Update()
+	assert.Nil(err)
+	assert.Equal(&CacheStats{Fetched: 9}, stats)
+
+	// fast-forward
+	git("-C", ".tmp", "checkout", "master")
+	git("-C", ".tmp", "merge", "--ff-only", "feature")
+
+	stats, err = cache.Update()
+	assert.Nil(err)
+	assert.Equal(&CacheStats{}, stats)
+
+	// force push
+	git("-C", ".tmp", "reset", "--hard", "HEAD~2")
+	git("-C", ".tmp", "commit", "--allow-empty", "-m", "feat(c): Add c")
+	git("-C", ".tmp", "branch", "-f", "feature", "master")
+
+	stats, err = cache.Update()
+	assert.Nil(err)
+	assert.Equal(&CacheStats{Fetched: 1, Invalidated: 2, Rewritten: true}, stats)
+	assert.Equal(8, cache.Len())
+
+	commits, err := cache.Log(&RevNumber{2}, nil)
+	assert.Nil(err)
+	assert.Equal([]string{"feat(c): Add c", "chore(release): Bump version to v0.0.0"}, subjects(commits))
+
+	// invalidated commits are not loaded
+	cache, err = NewCache(&Config{Path: ".tmp"}, dir)
+	assert.Nil(err)
+	assert.Equal(8, cache.Len())
+
+	// deleted branch
+	git("-C", ".tmp", "checkout", "-b", "removed")
+	git("-C", ".tmp", "commit", "--allow-empty", "-m", "feat(d): Add d")
+	git("-C", ".tmp", "checkout", "master")
+
+	stats, err = cache.Update()
+	assert.Nil(err)
+	assert.Equal(&CacheStats{Fetched: 1}, stats)
+
+	git("-C", ".tmp", "branch", "-D", "removed")
+
+	stats, err = cache.Update()
+	assert.Nil(err)
+	assert.Equal(&CacheStats{Invalidated: 1, Rewritten: true}, stats)
+}
+
+func TestCacheNotCached(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	dir, err := ioutil.TempDir("", "gitlog-cache")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	cache, err := NewCache(&Config{Path: ".tmp"}, dir)
+	assert.Nil(err)
+
+	commits, err := cache.Log(&RevSymmetric{Left: "topic", Right: "master"}, nil)
+	assert.Nil(err)
+	assert.Equal(5, len(commits))
+	assert.Equal(SideRight, commits[0].Side)
+
+	_, err = cache.Log(nil, &Params{MergesOnly: true, IgnoreMerges: true})
+	assert.IsType(&ParamsError{}, err)
+
+	_, err = cache.Log(&RevRange{Old: "unknown", New: "HEAD"}, &Params{Verify: true})
+	assert.IsType(&RefError{}, err)
+}
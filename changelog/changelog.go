@@ -0,0 +1,376 @@
+// Package changelog is providing a means to generate CHANGELOG from git-log.
+package changelog
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	gitlog "github.com/tsuyoshiwada/go-gitlog"
+)
+
+// DefaultTemplate renders the same format as CHANGELOG.md of go-gitlog
+const DefaultTemplate = `{{ range .Releases }}## {{ .Title }}
+{{ if not .Date.IsZero }}
+> {{ date .Date }}
+{{ end }}{{ range .Sections }}
+### {{ .Title }}
+
+{{ range .Commits }}* {{ if .Note }}{{ .Note }}{{ else }}{{ .Subject }}{{ end }}
+{{ end }}{{ end }}
+
+{{ end }}`
+
+// DefaultRules groups the commits of Conventional Commits
+var DefaultRules = []*Rule{
+	{Title: "Features", Types: []string{"feat"}},
+	{Title: "Bug Fixes", Types: []string{"fix"}},
+	{Title: "Performance Improvements", Types: []string{"perf"}},
+	{Title: "Code Refactoring", Types: []string{"refactor"}},
+}
+
+// Rule groups the commits by the type of commit message
+type Rule struct {
+	Title string   // title of section
+	Types []string // types of commit message, e.g. "feat" of `feat(parser): Add foo feature`
+}
+
+// Options for generating CHANGELOG
+type Options struct {
+	Rules           []*Rule        // default DefaultRules
+	Template        string         // text/template, default DefaultTemplate
+	BreakingTitle   string         // default "Breaking Changes"
+	OtherTitle      string         // section for commits that do not match rules, dropped if empty
+	Unreleased      bool           // include commits since the latest release
+	UnreleasedTitle string         // default "Unreleased"
+	SkipPrerelease  bool           // prerelease tags are not treated as releases
+	Params          *gitlog.Params // params of git-log for each release
+}
+
+// Changelog is the data passed to the template
+type Changelog struct {
+	Releases []*Release // newest first
+}
+
+// Release is a section of the version
+type Release struct {
+	Title    string
+	Tag      *gitlog.Tag // nil for unreleased
+	Date     time.Time   // zero for unreleased
+	Sections []*Section
+}
+
+// Section groups the commits by Rule
+type Section struct {
+	Title   string
+	Commits []*Commit
+}
+
+// Commit with the parsed commit message
+type Commit struct {
+	*gitlog.Commit
+	Type     string // e.g. "feat"
+	Scope    string // e.g. "parser"
+	Subject  string // e.g. "Add foo feature"
+	Breaking bool
+	Note     string // text of `BREAKING CHANGE:` footer
+}
+
+// Git is the subset of gitlog.Client used by Generator
+type Git interface {
+	gitlog.GitLog
+	Tags(*gitlog.TagsParams) ([]*gitlog.Tag, error)
+}
+
+// Generator of CHANGELOG
+type Generator struct {
+	git      Git
+	options  *Options
+	template *template.Template
+}
+
+// New Generator
+func New(git Git, options *Options) (*Generator, error) {
+	opts := &Options{}
+	if options != nil {
+		*opts = *options
+	}
+
+	if opts.Rules == nil {
+		opts.Rules = DefaultRules
+	}
+
+	if opts.Template == "" {
+		opts.Template = DefaultTemplate
+	}
+
+	if opts.BreakingTitle == "" {
+		opts.BreakingTitle = "Breaking Changes"
+	}
+
+	if opts.UnreleasedTitle == "" {
+		opts.UnreleasedTitle = "Unreleased"
+	}
+
+	tmpl, err := template.New("changelog").Funcs(template.FuncMap{
+		"date": func(t time.Time) string {
+			return t.Format("2006-01-02")
+		},
+	}).Parse(opts.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Generator{
+		git:      git,
+		options:  opts,
+		template: tmpl,
+	}, nil
+}
+
+// Generate returns Changelog of all releases
+func (g *Generator) Generate() (*Changelog, error) {
+	return g.generate(nil)
+}
+
+// generate releases newer than since, all releases if since is nil
+func (g *Generator) generate(since *gitlog.Version) (*Changelog, error) {
+	tags, err := g.git.Tags(&gitlog.TagsParams{
+		Filter:  g.tagFilter(),
+		Reverse: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	releases := []*Release{}
+
+	if g.options.Unreleased {
+		release, err := g.release("HEAD", nil, &Release{Title: g.options.UnreleasedTitle})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(release.Sections) > 0 {
+			releases = append(releases, release)
+		}
+	}
+
+	for _, tag := range tags {
+		if tag.Version == nil || (since != nil && tag.Version.Compare(since) <= 0) {
+			continue
+		}
+
+		release, err := g.release(tag.Name, tag.Version, &Release{
+			Title: tag.Name,
+			Tag:   tag,
+			Date:  tag.Date,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		releases = append(releases, release)
+	}
+
+	return &Changelog{
+		Releases: releases,
+	}, nil
+}
+
+// tagFilter returns the filter of the releases
+func (g *Generator) tagFilter() gitlog.TagFilter {
+	if g.options.SkipPrerelease {
+		return gitlog.TagFilterStable
+	}
+	return gitlog.TagFilterAll
+}
+
+// release fills the commits since the previous release, in the same way as gitlog.Client.ReleaseRange
+// the tags reachable from ref are listed once by `--merged` in descending order, so the previous release is the first one less than current.
+func (g *Generator) release(ref string, current *gitlog.Version, release *Release) (*Release, error) {
+	merged, err := g.git.Tags(&gitlog.TagsParams{
+		Merged:  ref,
+		Filter:  g.tagFilter(),
+		Reverse: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rev gitlog.RevArgs = &gitlog.Rev{Ref: ref}
+
+	for _, tag := range merged {
+		if tag.Version == nil || tag.Name == ref || (current != nil && tag.Version.Compare(current) >= 0) {
+			continue
+		}
+
+		rev = &gitlog.RevRange{Old: tag.Name, New: ref}
+		break
+	}
+
+	commits, err := g.git.Log(rev, g.options.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	release.Sections = g.group(commits)
+
+	return release, nil
+}
+
+func (g *Generator) group(commits []*gitlog.Commit) []*Section {
+	breaking := &Section{Title: g.options.BreakingTitle}
+	sections := make([]*Section, len(g.options.Rules))
+	other := &Section{Title: g.options.OtherTitle}
+
+	for i, rule := range g.options.Rules {
+		sections[i] = &Section{Title: rule.Title}
+	}
+
+	for _, c := range commits {
+		commit := ParseCommit(c)
+
+		if commit.Breaking {
+			breaking.Commits = append(breaking.Commits, commit)
+			continue
+		}
+
+		section := other
+	rules:
+		for i, rule := range g.options.Rules {
+			for _, typ := range rule.Types {
+				if typ == commit.Type {
+					section = sections[i]
+					break rules
+				}
+			}
+		}
+
+		section.Commits = append(section.Commits, commit)
+	}
+
+	if other.Title != "" {
+		sections = append(sections, other)
+	}
+
+	results := []*Section{}
+	for _, section := range append([]*Section{breaking}, sections...) {
+		if len(section.Commits) > 0 {
+			results = append(results, section)
+		}
+	}
+
+	return results
+}
+
+var (
+	headerRegex   = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+	breakingRegex = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:[ \t]*`)
+	trailerRegex  = regexp.MustCompile(`^[\w-]+(?::\s|\s#)`) // e.g. `Signed-off-by: ...`, `Closes #12`
+)
+
+// ParseCommit parses the commit message such as `feat(parser)!: Add foo feature`
+// commits that do not follow the format have the whole subject as Subject
+func ParseCommit(c *gitlog.Commit) *Commit {
+	commit := &Commit{
+		Commit:  c,
+		Subject: c.Subject,
+	}
+
+	if res := headerRegex.FindStringSubmatch(c.Subject); res != nil {
+		commit.Type = res[1]
+		commit.Scope = res[2]
+		commit.Breaking = res[3] != ""
+		commit.Subject = res[4]
+	}
+
+	if note, ok := parseBreakingNote(c.Body); ok {
+		commit.Breaking = true
+		commit.Note = note
+	}
+
+	return commit
+}
+
+// parseBreakingNote returns the paragraph of `BREAKING CHANGE:` footer, which ends at a blank line or the next trailer
+func parseBreakingNote(body string) (string, bool) {
+	loc := breakingRegex.FindStringIndex(body)
+	if loc == nil {
+		return "", false
+	}
+
+	lines := []string{}
+
+	for i, line := range strings.Split(body[loc[1]:], "\n") {
+		if i > 0 && (strings.TrimSpace(line) == "" || trailerRegex.MatchString(line)) {
+			break
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n")), true
+}
+
+// Render writes Changelog with the template
+func (g *Generator) Render(w io.Writer, changelog *Changelog) error {
+	return g.template.Execute(w, changelog)
+}
+
+var releaseTitleRegex = regexp.MustCompile(`(?m)^## (.+?)\s*$`)
+
+// Prepend generates the releases newer than the latest release written in the file, and prepends them
+// the unreleased section in the file is replaced
+func (g *Generator) Prepend(filename string) error {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	existing := string(content)
+	preamble := ""
+	var since *gitlog.Version
+
+	if loc := releaseTitleRegex.FindStringIndex(existing); loc != nil {
+		preamble = existing[:loc[0]]
+		existing = existing[loc[0]:]
+	}
+
+	// drop the unreleased section to regenerate
+	if res := releaseTitleRegex.FindStringSubmatch(existing); res != nil && res[1] == g.options.UnreleasedTitle {
+		existing = existing[len(res[0]):]
+		if loc := releaseTitleRegex.FindStringIndex(existing); loc != nil {
+			existing = existing[loc[0]:]
+		} else {
+			existing = ""
+		}
+	}
+
+	for _, res := range releaseTitleRegex.FindAllStringSubmatch(existing, -1) {
+		version, err := gitlog.ParseVersion(res[1])
+		if err == nil && (since == nil || version.Compare(since) > 0) {
+			since = version
+		}
+	}
+
+	changelog, err := g.generate(since)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(preamble)
+
+	if err := g.Render(&buf, changelog); err != nil {
+		return err
+	}
+
+	buf.WriteString(existing)
+
+	return ioutil.WriteFile(filename, []byte(strings.TrimRight(buf.String(), "\n")+"\n"), 0644)
+}
@@ -0,0 +1,290 @@
+package changelog
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	gitlog "github.com/tsuyoshiwada/go-gitlog"
+)
+
+func git(env []string, args ...string) string {
+	cmd := exec.Command("git", append([]string{"-C", ".tmp"}, args...)...)
+	cmd.Env = append(os.Environ(), env...)
+	bytes, _ := cmd.Output()
+	return string(bytes)
+}
+
+func gitCommit(date, msg string) string {
+	return git([]string{
+		"GIT_AUTHOR_DATE=" + date,
+		"GIT_COMMITTER_DATE=" + date,
+	}, "commit", "--allow-empty", "-m", msg)
+}
+
+func setup() func() {
+	dir, _ := filepath.Abs(".tmp")
+
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		log.Fatalln(err)
+	}
+
+	git(nil, "init")
+	git(nil, "config", "--local", "user.name", "authorname")
+	git(nil, "config", "--local", "user.email", "mail@example.com")
+
+	gitCommit("2018-01-31T12:00:00Z", "chore(*): Initial Commit")
+	git(nil, "tag", "v0.0.1")
+
+	gitCommit("2018-02-02T12:00:00Z", "feat(fields): Add `Tag` field in commit")
+	gitCommit("2018-02-02T12:00:01Z", "docs(readme): Update usage")
+	git(nil, "tag", "v0.0.2")
+
+	gitCommit("2018-02-06T12:00:00Z", "refactor(*): Use go-gitcmd")
+	gitCommit("2018-02-06T12:00:01Z", "feat(config)!: Rename to `Bin` from `GitBin`")
+	gitCommit("2018-02-06T12:00:02Z", "fix(parser): Fix crash on empty body\n\nBREAKING CHANGE: Tag is always non-nil")
+	git(nil, "tag", "v1.0.0")
+
+	gitCommit("2018-02-07T12:00:00Z", "perf(parser): Reduce allocations")
+	gitCommit("2018-02-07T12:00:01Z", "Update README")
+
+	return func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
+
+func TestParseCommit(t *testing.T) {
+	assert := assert.New(t)
+
+	table := []struct {
+		subject string
+		body    string
+		expect  *Commit
+	}{
+		{"feat(parser): Add foo feature", "", &Commit{Type: "feat", Scope: "parser", Subject: "Add foo feature"}},
+		{"fix: Fix bar", "", &Commit{Type: "fix", Subject: "Fix bar"}},
+		{"feat(*)!: Drop Go 1.8", "", &Commit{Type: "feat", Scope: "*", Subject: "Drop Go 1.8", Breaking: true}},
+		{"refactor: Rename", "Body\n\nBREAKING CHANGE: `GitBin` is renamed\n", &Commit{Type: "refactor", Subject: "Rename", Breaking: true, Note: "`GitBin` is renamed"}},
+		{"Merge pull request #12 from tsuyoshiwada/topic", "", &Commit{Subject: "Merge pull request #12 from tsuyoshiwada/topic"}},
+		{"feat: Drop", "BREAKING CHANGE: Foo is removed.\nUse Bar instead.\n\nOther paragraph", &Commit{Type: "feat", Subject: "Drop", Breaking: true, Note: "Foo is removed.\nUse Bar instead."}},
+		{"feat: Drop", "BREAKING-CHANGE:\nFoo is removed.\nSigned-off-by: foo <foo@example.com>", &Commit{Type: "feat", Subject: "Drop", Breaking: true, Note: "Foo is removed."}},
+		{"feat: Drop", "BREAKING CHANGE: Foo is removed.\nCloses #12", &Commit{Type: "feat", Subject: "Drop", Breaking: true, Note: "Foo is removed."}},
+	}
+
+	for _, expect := range table {
+		c := &gitlog.Commit{Subject: expect.subject, Body: expect.body}
+		expect.expect.Commit = c
+		assert.Equal(expect.expect, ParseCommit(c))
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	generator, err := New(gitlog.NewClient(&gitlog.Config{Path: ".tmp"}), &Options{
+		Unreleased: true,
+	})
+	assert.Nil(err)
+
+	changelog, err := generator.Generate()
+	assert.Nil(err)
+	assert.Equal(4, len(changelog.Releases))
+
+	unreleased := changelog.Releases[0]
+	assert.Equal("Unreleased", unreleased.Title)
+	assert.Nil(unreleased.Tag)
+	assert.True(unreleased.Date.IsZero())
+	assert.Equal(1, len(unreleased.Sections))
+	assert.Equal("Performance Improvements", unreleased.Sections[0].Title)
+
+	release := changelog.Releases[1]
+	assert.Equal("v1.0.0", release.Title)
+	assert.Equal("v1.0.0", release.Tag.Name)
+	assert.Equal(2, len(release.Sections))
+	assert.Equal("Breaking Changes", release.Sections[0].Title)
+	assert.Equal(2, len(release.Sections[0].Commits))
+	assert.Equal("Code Refactoring", release.Sections[1].Title)
+
+	var buf bytes.Buffer
+	assert.Nil(generator.Render(&buf, changelog))
+	assert.Equal("## Unreleased"+`
+
+### Performance Improvements
+
+* Reduce allocations
+
+
+## v1.0.0
+
+> 2018-02-06
+
+### Breaking Changes
+
+* Tag is always non-nil
+* Rename to `+"`Bin` from `GitBin`"+`
+
+### Code Refactoring
+
+* Use go-gitcmd
+
+
+## v0.0.2
+
+> 2018-02-02
+
+### Features
+
+* Add `+"`Tag`"+` field in commit
+
+
+## v0.0.1
+
+> 2018-01-31
+
+
+`, buf.String())
+}
+
+// countingGit counts the calls of Tags, with and without `--merged`
+type countingGit struct {
+	*gitlog.Client
+	tags   int
+	merged int
+}
+
+func (git *countingGit) Tags(params *gitlog.TagsParams) ([]*gitlog.Tag, error) {
+	if params != nil && params.Merged != "" {
+		git.merged++
+	} else {
+		git.tags++
+	}
+	return git.Client.Tags(params)
+}
+
+func TestGenerateListsTagsOnce(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	git := &countingGit{Client: gitlog.NewClient(&gitlog.Config{Path: ".tmp"})}
+
+	generator, err := New(git, &Options{Unreleased: true, OtherTitle: "Other"})
+	assert.Nil(err)
+
+	changelog, err := generator.Generate()
+	assert.Nil(err)
+	assert.Equal(4, len(changelog.Releases))
+	assert.Equal(1, git.tags)
+	assert.Equal(len(changelog.Releases), git.merged)
+
+	// same ranges as ReleaseRange
+	for _, release := range changelog.Releases[1:] {
+		rev, err := git.ReleaseRange(release.Tag.Name, nil)
+		assert.Nil(err)
+
+		commits, err := git.Log(rev, nil)
+		assert.Nil(err)
+
+		count := 0
+		for _, section := range release.Sections {
+			count += len(section.Commits)
+		}
+		assert.Equal(len(commits), count, release.Title)
+	}
+}
+
+func TestGenerateOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	generator, err := New(gitlog.NewClient(&gitlog.Config{Path: ".tmp"}), &Options{
+		Rules: []*Rule{
+			{Title: "Changes", Types: []string{"feat", "fix", "perf"}},
+		},
+		Template:   `{{ range .Releases }}{{ .Title }}:{{ range .Sections }} [{{ .Title }}]{{ range .Commits }} {{ .Type }}{{ end }}{{ end }}{{ "\n" }}{{ end }}`,
+		OtherTitle: "Others",
+		Params: &gitlog.Params{
+			Reverse: true,
+		},
+	})
+	assert.Nil(err)
+
+	changelog, err := generator.Generate()
+	assert.Nil(err)
+
+	var buf bytes.Buffer
+	assert.Nil(generator.Render(&buf, changelog))
+	assert.Equal(`v1.0.0: [Breaking Changes] feat fix [Others] refactor
+v0.0.2: [Changes] feat [Others] docs
+v0.0.1: [Others] chore
+`, buf.String())
+
+	_, err = New(nil, &Options{Template: "{{ .Foo "})
+	assert.NotNil(err)
+}
+
+func TestPrepend(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	filename := filepath.Join(".tmp", "CHANGELOG.md")
+
+	ioutil.WriteFile(filename, []byte(`# CHANGELOG
+
+## Unreleased
+
+### Features
+
+* Stale entry
+
+
+## v0.0.2
+
+> 2018-02-02
+
+* Hand-written entry
+`), 0644)
+
+	generator, _ := New(gitlog.NewClient(&gitlog.Config{Path: ".tmp"}), &Options{
+		Unreleased: true,
+		Template:   `{{ range .Releases }}## {{ .Title }}{{ "\n\n" }}{{ end }}`,
+	})
+
+	assert.Nil(generator.Prepend(filename))
+
+	content, _ := ioutil.ReadFile(filename)
+	assert.Equal(`# CHANGELOG
+
+## Unreleased
+
+## v1.0.0
+
+## v0.0.2
+
+> 2018-02-02
+
+* Hand-written entry
+`, string(content))
+
+	// new file
+	filename = filepath.Join(".tmp", "NEW.md")
+	assert.Nil(generator.Prepend(filename))
+
+	content, _ = ioutil.ReadFile(filename)
+	assert.Equal("## Unreleased\n\n## v1.0.0\n\n## v0.0.2\n\n## v0.0.1\n", string(content))
+}
@@ -0,0 +1,295 @@
+// Command gitlog prints git-log in text, JSON, NDJSON, CSV or a custom template.
+//
+//	gitlog [flags] [<revision expression>]
+//
+// The revision expression is parsed by gitlog.ParseRev, e.g. `v1.0.0..HEAD` or `master ^topic`.
+//
+// Exit codes:
+//
+//	0  success
+//	1  unexpected error
+//	2  invalid flags
+//	3  not a git repository
+//	4  bad revision
+//	5  git command is not found
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	gitlog "github.com/tsuyoshiwada/go-gitlog"
+)
+
+// List of exit code
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitNotRepository
+	exitBadRevision
+	exitGitNotFound
+)
+
+// usageError is the error of flags
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+var orders = map[string]gitlog.Order{
+	"":            gitlog.OrderDefault,
+	"date":        gitlog.OrderDate,
+	"author-date": gitlog.OrderAuthorDate,
+	"topo":        gitlog.OrderTopo,
+}
+
+var formats = map[string]*gitlog.Format{
+	"text":    gitlog.FormatMedium(),
+	"oneline": gitlog.FormatOneline(),
+	"medium":  gitlog.FormatMedium(),
+	"fuller":  gitlog.FormatFuller(),
+}
+
+type options struct {
+	path     string
+	bin      string
+	all      bool
+	number   int
+	skip     int
+	since    string
+	until    string
+	params   gitlog.Params
+	order    string
+	format   string
+	template string
+	columns  string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	opts := &options{}
+
+	fs := flag.NewFlagSet("gitlog", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gitlog [flags] [<revision expression>]")
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&opts.path, "C", ".", "path of the repository")
+	fs.StringVar(&opts.bin, "git", "git", "path of the git command")
+	fs.BoolVar(&opts.all, "all", false, "all refs")
+	fs.IntVar(&opts.number, "n", 0, "limit the number of commits")
+	fs.IntVar(&opts.skip, "skip", 0, "skip the number of commits")
+	fs.StringVar(&opts.since, "since", "", "commits more recent than the `date` (2006-01-02, RFC 3339 or \"2 weeks ago\")")
+	fs.StringVar(&opts.until, "until", "", "commits older than the `date` (2006-01-02, RFC 3339 or \"2 weeks ago\")")
+	fs.BoolVar(&opts.params.MergesOnly, "merges", false, "merge commits only")
+	fs.BoolVar(&opts.params.IgnoreMerges, "no-merges", false, "ignore merge commits")
+	fs.BoolVar(&opts.params.Reverse, "reverse", false, "reverse order")
+	fs.BoolVar(&opts.params.FirstParent, "first-parent", false, "follow only the first parent")
+	fs.BoolVar(&opts.params.AncestryPath, "ancestry-path", false, "commits on the ancestry path of the range")
+	fs.BoolVar(&opts.params.SimplifyByDecoration, "simplify-by-decoration", false, "commits referred by refs only")
+	fs.BoolVar(&opts.params.Boundary, "boundary", false, "output boundary commits")
+	fs.BoolVar(&opts.params.Mailmap, "mailmap", false, "map identities by .mailmap")
+	fs.StringVar(&opts.order, "order", "", "order of commits (date, author-date or topo)")
+	fs.StringVar(&opts.format, "format", "text", "output format (text, oneline, medium, fuller, json, ndjson or csv)")
+	fs.StringVar(&opts.template, "template", "", "text/template of each commit, overrides -format")
+	fs.StringVar(&opts.columns, "columns", "", "comma separated columns of csv")
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	err := execute(opts, strings.Join(fs.Args(), " "), stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "gitlog: %s\n", err)
+	}
+
+	return exitCode(err)
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	switch err.(type) {
+	case *usageError, *gitlog.ParamsError:
+		return exitUsage
+	case *gitlog.NotRepositoryError:
+		return exitNotRepository
+	case *gitlog.InvalidRevError, *gitlog.RevParseError, *gitlog.RefError, *gitlog.RevConflictError:
+		return exitBadRevision
+	case *gitlog.GitNotFoundError:
+		return exitGitNotFound
+	}
+
+	return exitError
+}
+
+func execute(opts *options, expr string, w io.Writer) error {
+	rev, err := buildRev(opts, expr)
+	if err != nil {
+		return err
+	}
+
+	order, ok := orders[opts.order]
+	if !ok {
+		return &usageError{fmt.Sprintf("\"%s\" is unknown order", opts.order)}
+	}
+
+	params := opts.params
+	params.Order = order
+	params.Verify = true
+
+	write, err := buildWriter(opts)
+	if err != nil {
+		return err
+	}
+
+	git := gitlog.New(&gitlog.Config{
+		Bin:  opts.bin,
+		Path: opts.path,
+	})
+
+	commits, err := git.Log(rev, &params)
+	if err != nil {
+		return err
+	}
+
+	return write(w, commits)
+}
+
+func buildRev(opts *options, expr string) (gitlog.RevArgs, error) {
+	revs := []gitlog.RevArgs{}
+
+	if opts.all {
+		revs = append(revs, &gitlog.RevAll{})
+	}
+
+	if expr != "" {
+		rev, err := gitlog.ParseRev(expr)
+		if err != nil {
+			return nil, err
+		}
+		revs = append(revs, rev)
+	}
+
+	if opts.number > 0 {
+		revs = append(revs, &gitlog.RevNumber{Limit: opts.number})
+	}
+
+	if opts.skip > 0 {
+		revs = append(revs, &gitlog.RevSkip{Offset: opts.skip})
+	}
+
+	if opts.since != "" || opts.until != "" {
+		rev := &gitlog.RevTime{}
+
+		for _, t := range []struct {
+			value string
+			time  *time.Time
+		}{
+			{opts.since, &rev.Since},
+			{opts.until, &rev.Until},
+		} {
+			if t.value == "" {
+				continue
+			}
+
+			parsed, err := parseTime(t.value)
+			if err != nil {
+				return nil, err
+			}
+			*t.time = parsed
+		}
+
+		revs = append(revs, rev)
+	}
+
+	switch len(revs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return revs[0], nil
+	}
+
+	return &gitlog.RevGroup{Revs: revs}, nil
+}
+
+// parseTime parses date, RFC 3339 or relative expression
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if rev, err := gitlog.ParseRevTime(value); err == nil {
+		return rev.Since, nil
+	}
+
+	return time.Time{}, &usageError{fmt.Sprintf("\"%s\" is invalid date", value)}
+}
+
+func buildWriter(opts *options) (func(io.Writer, []*gitlog.Commit) error, error) {
+	if opts.template != "" {
+		return formatWriter(&gitlog.Format{Template: opts.template + "\n"})
+	}
+
+	switch opts.format {
+	case "json":
+		return gitlog.EncodeJSON, nil
+	case "ndjson":
+		return gitlog.EncodeNDJSON, nil
+	case "csv":
+		columns := []gitlog.CSVColumn{}
+		if opts.columns != "" {
+			for _, column := range strings.Split(opts.columns, ",") {
+				columns = append(columns, gitlog.CSVColumn(strings.TrimSpace(column)))
+			}
+		}
+
+		// Check columns before running git
+		if err := gitlog.EncodeCSV(ioutil.Discard, nil, columns); err != nil {
+			return nil, &usageError{err.Error()}
+		}
+
+		return func(w io.Writer, commits []*gitlog.Commit) error {
+			return gitlog.EncodeCSV(w, commits, columns)
+		}, nil
+	}
+
+	format, ok := formats[opts.format]
+	if !ok {
+		return nil, &usageError{fmt.Sprintf("\"%s\" is unknown format", opts.format)}
+	}
+
+	return formatWriter(format)
+}
+
+func formatWriter(format *gitlog.Format) (func(io.Writer, []*gitlog.Commit) error, error) {
+	formatter, err := gitlog.NewFormatter(format)
+	if err != nil {
+		return nil, &usageError{err.Error()}
+	}
+
+	return formatter.Format, nil
+}
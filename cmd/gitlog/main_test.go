@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	gitlog "github.com/tsuyoshiwada/go-gitlog"
+)
+
+func git(args ...string) string {
+	cmd := exec.Command("git", append([]string{"-C", ".tmp"}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_DATE=2018-01-31T12:00:00Z",
+		"GIT_COMMITTER_DATE=2018-01-31T12:00:00Z",
+	)
+	bytes, _ := cmd.Output()
+	return string(bytes)
+}
+
+func setup() func() {
+	dir, _ := filepath.Abs(".tmp")
+
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		log.Fatalln(err)
+	}
+
+	git("init")
+	git("config", "--local", "user.name", "authorname")
+	git("config", "--local", "user.email", "mail@example.com")
+
+	git("commit", "--allow-empty", "-m", "chore(*): Initial Commit")
+	git("tag", "v1.0.0")
+	git("commit", "--allow-empty", "-m", "feat(parser): Add foo feature")
+	git("commit", "--allow-empty", "-m", "fix(logger): Fix bar function")
+
+	return func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
+
+func gitlogRun(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	code, out, _ := gitlogRun("-C", ".tmp", "-template", "{{ .Subject }}")
+	assert.Equal(exitOK, code)
+	assert.Equal("fix(logger): Fix bar function\nfeat(parser): Add foo feature\nchore(*): Initial Commit\n", out)
+
+	code, out, _ = gitlogRun("-C", ".tmp", "-template", "{{ .Subject }}", "-reverse", "v1.0.0..HEAD")
+	assert.Equal(exitOK, code)
+	assert.Equal("feat(parser): Add foo feature\nfix(logger): Fix bar function\n", out)
+
+	code, out, _ = gitlogRun("-C", ".tmp", "-format", "oneline", "-n", "1", "-skip", "1")
+	assert.Equal(exitOK, code)
+	assert.True(strings.HasSuffix(out, " feat(parser): Add foo feature\n"))
+
+	code, out, _ = gitlogRun("-C", ".tmp", "-template", "{{ .Subject }}", "-since", "2018-02-01")
+	assert.Equal(exitOK, code)
+	assert.Equal("", out)
+
+	code, out, _ = gitlogRun("-C", ".tmp", "-template", "{{ .Subject }}", "-until", "2018-02-01T00:00:00Z", "-n", "1")
+	assert.Equal(exitOK, code)
+	assert.Equal("fix(logger): Fix bar function\n", out)
+
+	code, out, _ = gitlogRun("-C", ".tmp", "-format", "json")
+	assert.Equal(exitOK, code)
+	commits, err := gitlog.DecodeJSON(strings.NewReader(out))
+	assert.Nil(err)
+	assert.Equal(3, len(commits))
+	assert.Equal("authorname", commits[0].Author.Name)
+
+	code, out, _ = gitlogRun("-C", ".tmp", "-format", "ndjson", "HEAD~1")
+	assert.Equal(exitOK, code)
+	assert.Equal(2, strings.Count(out, "\n"))
+
+	code, out, _ = gitlogRun("-C", ".tmp", "-format", "csv", "-columns", "subject,author_name", "-n", "1")
+	assert.Equal(exitOK, code)
+	assert.Equal("subject,author_name\nfix(logger): Fix bar function,authorname\n", out)
+
+	code, out, _ = gitlogRun("-C", ".tmp")
+	assert.Equal(exitOK, code)
+	assert.Contains(out, "Author: authorname <mail@example.com>\n")
+}
+
+func TestRunExitCodes(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	dir, _ := ioutil.TempDir("", "gitlog")
+	defer os.RemoveAll(dir)
+
+	table := []struct {
+		args []string
+		code int
+	}{
+		{[]string{"-unknown"}, exitUsage},
+		{[]string{"-C", ".tmp", "-format", "xml"}, exitUsage},
+		{[]string{"-C", ".tmp", "-format", "csv", "-columns", "foo"}, exitUsage},
+		{[]string{"-C", ".tmp", "-order", "random"}, exitUsage},
+		{[]string{"-C", ".tmp", "-since", "someday"}, exitUsage},
+		{[]string{"-C", ".tmp", "-template", "{{ .Foo "}, exitUsage},
+		{[]string{"-C", ".tmp", "-merges", "-no-merges"}, exitUsage},
+		{[]string{"-C", dir}, exitNotRepository},
+		{[]string{"-C", "/notfound/repo"}, exitNotRepository},
+		{[]string{"-C", ".tmp", "notfound"}, exitBadRevision},
+		{[]string{"-C", ".tmp", "v1.0.0..", "^"}, exitBadRevision},
+		{[]string{"-C", ".tmp", "--", "--output=/tmp/x"}, exitBadRevision},
+		{[]string{"-C", ".tmp", "-git", "/notfound/git/bin"}, exitGitNotFound},
+	}
+
+	for _, test := range table {
+		code, _, stderr := gitlogRun(test.args...)
+		assert.Equal(test.code, code, strings.Join(test.args, " "))
+		assert.NotEqual("", stderr)
+	}
+}
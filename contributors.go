@@ -0,0 +1,220 @@
+package gitlog
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContributorIdentity is the identity to group commits by
+type ContributorIdentity int
+
+// List of ContributorIdentity
+const (
+	ContributorAuthor ContributorIdentity = iota
+	ContributorCommitter
+)
+
+// ContributorSort is the order of contributors
+type ContributorSort int
+
+// List of ContributorSort
+const (
+	ContributorSortCommits ContributorSort = iota // number of commits in descending order
+	ContributorSortName                           // name in ascending order
+	ContributorSortLines                          // lines added and removed in descending order
+	ContributorSortFirst                          // first commit date in ascending order
+	ContributorSortLast                           // last commit date in descending order
+)
+
+// ContributorsParams for aggregating contributors
+type ContributorsParams struct {
+	By        ContributorIdentity
+	Sort      ContributorSort
+	Reverse   bool
+	Stats     bool    // count lines added and removed (`--numstat`)
+	CoAuthors bool    // credit `Co-authored-by:` trailers with the commit, the lines are credited to the identity only
+	Params    *Params // params of git-log
+}
+
+// Contributor is the aggregation of commits by identity
+type Contributor struct {
+	Name    string // name of the most recent commit by date
+	Email   string
+	Commits int
+	First   time.Time // date of the first commit
+	Last    time.Time // date of the last commit
+	Added   int       // filled only if Stats is true
+	Removed int       // filled only if Stats is true
+}
+
+var coAuthorRegex = regexp.MustCompile(`(?mi)^co-authored-by:[ \t]*(.*?)[ \t]*<([^>]*)>[ \t]*$`)
+
+// Contributors aggregates the commits of rev like git-shortlog
+func (gitLog *Client) Contributors(rev RevArgs, params *ContributorsParams) ([]*Contributor, error) {
+	if params == nil {
+		params = &ContributorsParams{}
+	}
+
+	commits, stats, err := gitLog.log(rev, params.Params, params.Stats)
+	if err != nil {
+		return nil, err
+	}
+
+	return aggregate(commits, stats, params), nil
+}
+
+// Aggregate groups the commits by identity, Stats is ignored
+func Aggregate(commits []*Commit, params *ContributorsParams) []*Contributor {
+	if params == nil {
+		params = &ContributorsParams{}
+	}
+
+	return aggregate(commits, nil, params)
+}
+
+func aggregate(commits []*Commit, stats map[string][2]int, params *ContributorsParams) []*Contributor {
+	contributors := []*Contributor{}
+	index := map[string]*Contributor{}
+
+	credit := func(name, email string, date time.Time, lines [2]int) {
+		key := strings.ToLower(email)
+		if key == "" {
+			key = name
+		}
+
+		contributor, ok := index[key]
+		if !ok {
+			contributor = &Contributor{
+				Name:  name,
+				Email: email,
+				First: date,
+				Last:  date,
+			}
+			index[key] = contributor
+			contributors = append(contributors, contributor)
+		}
+
+		contributor.Commits++
+		contributor.Added += lines[0]
+		contributor.Removed += lines[1]
+
+		if date.Before(contributor.First) {
+			contributor.First = date
+		}
+
+		if date.After(contributor.Last) {
+			contributor.Name = name
+			contributor.Last = date
+		}
+	}
+
+	for _, commit := range commits {
+		var name, email string
+		var date time.Time
+
+		if params.By == ContributorCommitter && commit.Committer != nil {
+			name, email, date = commit.Committer.Name, commit.Committer.Email, commit.Committer.Date
+		} else if commit.Author != nil {
+			name, email, date = commit.Author.Name, commit.Author.Email, commit.Author.Date
+		}
+
+		var lines [2]int
+		if commit.Hash != nil {
+			lines = stats[commit.Hash.Long]
+		}
+
+		credit(name, email, date, lines)
+
+		if !params.CoAuthors {
+			continue
+		}
+
+		credited := map[string]bool{strings.ToLower(email): true}
+
+		for _, res := range coAuthorRegex.FindAllStringSubmatch(commit.Body, -1) {
+			key := strings.ToLower(res[2])
+			if credited[key] {
+				continue
+			}
+			credited[key] = true
+
+			// the lines are not split among the co-authors, so the totals are not counted twice
+			credit(res[1], res[2], date, [2]int{})
+		}
+	}
+
+	sortContributors(contributors, params.Sort)
+
+	if params.Reverse {
+		for i, j := 0, len(contributors)-1; i < j; i, j = i+1, j-1 {
+			contributors[i], contributors[j] = contributors[j], contributors[i]
+		}
+	}
+
+	return contributors
+}
+
+func sortContributors(contributors []*Contributor, by ContributorSort) {
+	sort.SliceStable(contributors, func(i, j int) bool {
+		a, b := contributors[i], contributors[j]
+
+		switch by {
+		case ContributorSortCommits:
+			if a.Commits != b.Commits {
+				return a.Commits > b.Commits
+			}
+
+		case ContributorSortLines:
+			if la, lb := a.Added+a.Removed, b.Added+b.Removed; la != lb {
+				return la > lb
+			}
+
+		case ContributorSortFirst:
+			if !a.First.Equal(b.First) {
+				return a.First.Before(b.First)
+			}
+
+		case ContributorSortLast:
+			if !a.Last.Equal(b.Last) {
+				return a.Last.After(b.Last)
+			}
+		}
+
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+
+		return a.Email < b.Email
+	})
+}
+
+// parseNumstat returns the lines added and removed in the output of `--numstat`
+func parseNumstat(out string) [2]int {
+	var stats [2]int
+
+	for _, line := range strings.Split(out, "\n") {
+		// `<added>\t<removed>\t<path>`, binary files are `-`
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+
+		added, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+
+		removed, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+
+		stats[0] += added
+		stats[1] += removed
+	}
+
+	return stats
+}
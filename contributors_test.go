@@ -0,0 +1,145 @@
+package gitlog
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupContributors() func() {
+	clear := setup()
+
+	ioutil.WriteFile(filepath.Join(".tmp", "a.txt"), []byte("a\nb\nc\n"), 0644)
+	git("-C", ".tmp", "add", "a.txt")
+	git("-C", ".tmp", "commit", "--author", "Alice <alice@example.com>", "--date", "2018-02-01T00:00:00Z", "-m", "feat(a): Add a")
+
+	ioutil.WriteFile(filepath.Join(".tmp", "a.txt"), []byte("a\nB\nc\n"), 0644)
+	git("-C", ".tmp", "commit", "-a", "--author", "alice <ALICE@example.com>", "--date", "2018-02-03T00:00:00Z", "-m",
+		"fix(a): Fix b\n\nCo-authored-by: Bob <bob@example.com>\nCo-authored-by: Alice <alice@example.com>")
+
+	return clear
+}
+
+func TestContributors(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setupContributors()
+	defer clear()
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	contributors, err := gitLog.Contributors(nil, nil)
+	assert.Nil(err)
+	assert.Equal(2, len(contributors))
+	assert.Equal("authorname", contributors[0].Name)
+	assert.Equal("mail@example.com", contributors[0].Email)
+	assert.Equal(7, contributors[0].Commits)
+	assert.Equal("alice", contributors[1].Name)
+	assert.Equal(2, contributors[1].Commits)
+	assert.Equal(time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC).Unix(), contributors[1].First.Unix())
+	assert.Equal(time.Date(2018, 2, 3, 0, 0, 0, 0, time.UTC).Unix(), contributors[1].Last.Unix())
+	assert.Equal(0, contributors[1].Added)
+
+	rev := &RevRange{Old: "3.6.4-beta.12", New: "HEAD"}
+
+	contributors, err = gitLog.Contributors(rev, &ContributorsParams{
+		Stats:     true,
+		CoAuthors: true,
+		Sort:      ContributorSortName,
+	})
+	assert.Nil(err)
+	assert.Equal(2, len(contributors))
+	assert.Equal(&Contributor{
+		Name:    "Bob",
+		Email:   "bob@example.com",
+		Commits: 1,
+		First:   contributors[1].Last,
+		Last:    contributors[1].Last,
+	}, contributors[0])
+	assert.Equal("alice", contributors[1].Name)
+	assert.Equal(2, contributors[1].Commits)
+	assert.Equal(4, contributors[1].Added)
+	assert.Equal(1, contributors[1].Removed)
+
+	// the name of the most recent commit regardless of the order of git-log
+	contributors, err = gitLog.Contributors(rev, &ContributorsParams{
+		Params: &Params{Reverse: true},
+	})
+	assert.Nil(err)
+	assert.Equal(1, len(contributors))
+	assert.Equal("alice", contributors[0].Name)
+
+	contributors, err = gitLog.Contributors(rev, &ContributorsParams{
+		By: ContributorCommitter,
+	})
+	assert.Nil(err)
+	assert.Equal(1, len(contributors))
+	assert.Equal("authorname", contributors[0].Name)
+	assert.Equal(2, contributors[0].Commits)
+
+	_, err = gitLog.Contributors(rev, &ContributorsParams{
+		Params: &Params{MergesOnly: true, IgnoreMerges: true},
+	})
+	assert.IsType(&ParamsError{}, err)
+}
+
+func TestAggregate(t *testing.T) {
+	assert := assert.New(t)
+
+	date := func(day int) time.Time {
+		return time.Date(2018, 2, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	commit := func(name string, day int, body string) *Commit {
+		return &Commit{
+			Author:    &Author{Name: name, Email: name + "@example.com", Date: date(day)},
+			Committer: &Committer{Name: "committer", Email: "committer@example.com", Date: date(day)},
+			Body:      body,
+		}
+	}
+
+	commits := []*Commit{
+		commit("carol", 5, ""),
+		commit("bob", 4, "co-authored-by: Carol <carol@example.com>"),
+		commit("alice", 3, ""),
+		commit("bob", 1, ""),
+	}
+
+	names := func(contributors []*Contributor) []string {
+		names := []string{}
+		for _, contributor := range contributors {
+			names = append(names, contributor.Name)
+		}
+		return names
+	}
+
+	contributors := Aggregate(commits, nil)
+	assert.Equal([]string{"bob", "alice", "carol"}, names(contributors))
+	assert.Equal(date(1), contributors[0].First)
+	assert.Equal(date(4), contributors[0].Last)
+
+	contributors = Aggregate(commits, &ContributorsParams{CoAuthors: true})
+	assert.Equal([]string{"bob", "carol", "alice"}, names(contributors))
+	assert.Equal(2, contributors[1].Commits)
+	assert.Equal(date(4), contributors[1].First)
+
+	// the name of the most recent commit
+	contributors = Aggregate(append(commits, commit("Carol", 2, "")), nil)
+	assert.Equal("carol", contributors[1].Name)
+	assert.Equal(2, contributors[1].Commits)
+
+	contributors = Aggregate(commits, &ContributorsParams{Sort: ContributorSortFirst})
+	assert.Equal([]string{"bob", "alice", "carol"}, names(contributors))
+
+	contributors = Aggregate(commits, &ContributorsParams{Sort: ContributorSortLast, Reverse: true})
+	assert.Equal([]string{"alice", "bob", "carol"}, names(contributors))
+
+	contributors = Aggregate(commits, &ContributorsParams{By: ContributorCommitter})
+	assert.Equal([]string{"committer"}, names(contributors))
+	assert.Equal(4, contributors[0].Commits)
+}
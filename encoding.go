@@ -0,0 +1,493 @@
+package gitlog
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// The schema of JSON and NDJSON, dates are RFC 3339
+//
+//	{
+//	  "hash":       {"long": "51064a8...", "short": "51064a8"},
+//	  "tree":       {"long": "4b825dc...", "short": "4b825dc"},
+//	  "author":     {"name": "", "email": "", "date": "2018-01-28T11:32:41Z", "raw_name": "", "raw_email": ""},
+//	  "committer":  {"name": "", "email": "", "date": "2018-01-28T11:32:41Z", "raw_name": "", "raw_email": ""},
+//	  "tag":        "v1.0.0",
+//	  "subject":    "chore(*): Initial commit",
+//	  "body":       "",
+//	  "boundary":   false,
+//	  "side":       "left",
+//	  "equivalent": false
+//	}
+//
+// "raw_name", "raw_email", "boundary", "side" and "equivalent" are omitted if they are empty.
+// "side" is "left" or "right".
+
+type hashRecord struct {
+	Long  string `json:"long"`
+	Short string `json:"short"`
+}
+
+type identityRecord struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Date     string `json:"date"`
+	RawName  string `json:"raw_name,omitempty"`
+	RawEmail string `json:"raw_email,omitempty"`
+}
+
+type commitRecord struct {
+	Hash       hashRecord     `json:"hash"`
+	Tree       hashRecord     `json:"tree"`
+	Author     identityRecord `json:"author"`
+	Committer  identityRecord `json:"committer"`
+	Tag        string         `json:"tag"`
+	Subject    string         `json:"subject"`
+	Body       string         `json:"body"`
+	Boundary   bool           `json:"boundary,omitempty"`
+	Side       string         `json:"side,omitempty"`
+	Equivalent bool           `json:"equivalent,omitempty"`
+}
+
+var sideNames = map[Side]string{
+	SideNone:  "",
+	SideLeft:  "left",
+	SideRight: "right",
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func parseDate(str string) (time.Time, error) {
+	if str == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, str)
+}
+
+// parseBool parses the boolean of CSV, empty is false
+func parseBool(str string) (bool, error) {
+	if str == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(str)
+}
+
+func formatSide(side Side) string {
+	return sideNames[side]
+}
+
+func parseSide(str string) (Side, error) {
+	for side, name := range sideNames {
+		if name == str {
+			return side, nil
+		}
+	}
+	return SideNone, fmt.Errorf("\"%s\" is unknown side", str)
+}
+
+func newCommitRecord(commit *Commit) *commitRecord {
+	record := &commitRecord{
+		Subject:    commit.Subject,
+		Body:       commit.Body,
+		Boundary:   commit.Boundary,
+		Side:       formatSide(commit.Side),
+		Equivalent: commit.Equivalent,
+	}
+
+	if commit.Hash != nil {
+		record.Hash = hashRecord{commit.Hash.Long, commit.Hash.Short}
+	}
+
+	if commit.Tree != nil {
+		record.Tree = hashRecord{commit.Tree.Long, commit.Tree.Short}
+	}
+
+	if a := commit.Author; a != nil {
+		record.Author = identityRecord{a.Name, a.Email, formatDate(a.Date), a.RawName, a.RawEmail}
+	}
+
+	if c := commit.Committer; c != nil {
+		record.Committer = identityRecord{c.Name, c.Email, formatDate(c.Date), c.RawName, c.RawEmail}
+	}
+
+	if commit.Tag != nil {
+		record.Tag = commit.Tag.Name
+	}
+
+	return record
+}
+
+// commit returns Commit with the same shape as the one returned by Log
+func (record *commitRecord) commit() (*Commit, error) {
+	authorDate, err := parseDate(record.Author.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	committerDate, err := parseDate(record.Committer.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	side, err := parseSide(record.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Commit{
+		Hash: &Hash{
+			Long:  record.Hash.Long,
+			Short: record.Hash.Short,
+		},
+		Tree: &Tree{
+			Long:  record.Tree.Long,
+			Short: record.Tree.Short,
+		},
+		Author: &Author{
+			Name:     record.Author.Name,
+			Email:    record.Author.Email,
+			Date:     authorDate,
+			RawName:  record.Author.RawName,
+			RawEmail: record.Author.RawEmail,
+		},
+		Committer: &Committer{
+			Name:     record.Committer.Name,
+			Email:    record.Committer.Email,
+			Date:     committerDate,
+			RawName:  record.Committer.RawName,
+			RawEmail: record.Committer.RawEmail,
+		},
+		Tag: &Tag{
+			Name: record.Tag,
+			Date: authorDate,
+		},
+		Subject:    record.Subject,
+		Body:       record.Body,
+		Boundary:   record.Boundary,
+		Side:       side,
+		Equivalent: record.Equivalent,
+	}, nil
+}
+
+// EncodeJSON writes commits as JSON array
+func EncodeJSON(w io.Writer, commits []*Commit) error {
+	records := make([]*commitRecord, len(commits))
+	for i, commit := range commits {
+		records[i] = newCommitRecord(commit)
+	}
+
+	return json.NewEncoder(w).Encode(records)
+}
+
+// DecodeJSON reads commits written by EncodeJSON
+func DecodeJSON(r io.Reader) ([]*Commit, error) {
+	records := []*commitRecord{}
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, err
+	}
+
+	commits := make([]*Commit, len(records))
+	for i, record := range records {
+		commit, err := record.commit()
+		if err != nil {
+			return nil, err
+		}
+		commits[i] = commit
+	}
+
+	return commits, nil
+}
+
+// NDJSONEncoder writes a commit per line
+type NDJSONEncoder struct {
+	encoder *json.Encoder
+}
+
+// NewNDJSONEncoder returns NDJSONEncoder that writes to w
+func NewNDJSONEncoder(w io.Writer) *NDJSONEncoder {
+	return &NDJSONEncoder{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// Encode writes the commit and a newline
+func (e *NDJSONEncoder) Encode(commit *Commit) error {
+	return e.encoder.Encode(newCommitRecord(commit))
+}
+
+// NDJSONDecoder reads a commit per line
+type NDJSONDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewNDJSONDecoder returns NDJSONDecoder that reads from r
+func NewNDJSONDecoder(r io.Reader) *NDJSONDecoder {
+	scanner := bufio.NewScanner(r)
+
+	// the body of commit can be longer than the default limit
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	return &NDJSONDecoder{
+		scanner: scanner,
+	}
+}
+
+// Decode reads the next commit, io.EOF is returned at the end of input
+func (d *NDJSONDecoder) Decode() (*Commit, error) {
+	for d.scanner.Scan() {
+		d.line++
+
+		line := d.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		record := &commitRecord{}
+		if err := json.Unmarshal(line, record); err != nil {
+			return nil, fmt.Errorf("line %d: %s", d.line, err)
+		}
+
+		commit, err := record.commit()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", d.line, err)
+		}
+
+		return commit, nil
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+// EncodeNDJSON writes commits as NDJSON
+func EncodeNDJSON(w io.Writer, commits []*Commit) error {
+	encoder := NewNDJSONEncoder(w)
+
+	for _, commit := range commits {
+		if err := encoder.Encode(commit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeNDJSON reads all commits written by EncodeNDJSON
+func DecodeNDJSON(r io.Reader) ([]*Commit, error) {
+	decoder := NewNDJSONDecoder(r)
+	commits := []*Commit{}
+
+	for {
+		commit, err := decoder.Decode()
+		if err == io.EOF {
+			return commits, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		commits = append(commits, commit)
+	}
+}
+
+// CSVColumn is the column of CSV, it is also used for the header
+type CSVColumn string
+
+// List of CSVColumn
+const (
+	CSVHash              CSVColumn = "hash"
+	CSVShortHash         CSVColumn = "short_hash"
+	CSVTree              CSVColumn = "tree"
+	CSVShortTree         CSVColumn = "short_tree"
+	CSVAuthorName        CSVColumn = "author_name"
+	CSVAuthorEmail       CSVColumn = "author_email"
+	CSVAuthorDate        CSVColumn = "author_date"
+	CSVAuthorRawName     CSVColumn = "author_raw_name"
+	CSVAuthorRawEmail    CSVColumn = "author_raw_email"
+	CSVCommitterName     CSVColumn = "committer_name"
+	CSVCommitterEmail    CSVColumn = "committer_email"
+	CSVCommitterDate     CSVColumn = "committer_date"
+	CSVCommitterRawName  CSVColumn = "committer_raw_name"
+	CSVCommitterRawEmail CSVColumn = "committer_raw_email"
+	CSVTag               CSVColumn = "tag"
+	CSVSubject           CSVColumn = "subject"
+	CSVBody              CSVColumn = "body"
+	CSVBoundary          CSVColumn = "boundary"
+	CSVSide              CSVColumn = "side"
+	CSVEquivalent        CSVColumn = "equivalent"
+)
+
+// DefaultCSVColumns is used if columns are not given
+var DefaultCSVColumns = []CSVColumn{
+	CSVHash,
+	CSVShortHash,
+	CSVAuthorName,
+	CSVAuthorEmail,
+	CSVAuthorDate,
+	CSVCommitterName,
+	CSVCommitterEmail,
+	CSVCommitterDate,
+	CSVTag,
+	CSVSubject,
+	CSVBody,
+}
+
+// csvField returns the pointer to the field of record for column
+func csvField(record *commitRecord, column CSVColumn) (*string, error) {
+	fields := map[CSVColumn]*string{
+		CSVHash:              &record.Hash.Long,
+		CSVShortHash:         &record.Hash.Short,
+		CSVTree:              &record.Tree.Long,
+		CSVShortTree:         &record.Tree.Short,
+		CSVAuthorName:        &record.Author.Name,
+		CSVAuthorEmail:       &record.Author.Email,
+		CSVAuthorDate:        &record.Author.Date,
+		CSVAuthorRawName:     &record.Author.RawName,
+		CSVAuthorRawEmail:    &record.Author.RawEmail,
+		CSVCommitterName:     &record.Committer.Name,
+		CSVCommitterEmail:    &record.Committer.Email,
+		CSVCommitterDate:     &record.Committer.Date,
+		CSVCommitterRawName:  &record.Committer.RawName,
+		CSVCommitterRawEmail: &record.Committer.RawEmail,
+		CSVTag:               &record.Tag,
+		CSVSubject:           &record.Subject,
+		CSVBody:              &record.Body,
+		CSVSide:              &record.Side,
+	}
+
+	field, ok := fields[column]
+	if !ok {
+		return nil, fmt.Errorf("\"%s\" is unknown column", column)
+	}
+
+	return field, nil
+}
+
+// EncodeCSV writes commits as CSV with the header, DefaultCSVColumns is used if columns is empty
+func EncodeCSV(w io.Writer, commits []*Commit, columns []CSVColumn) error {
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+
+	writer := csv.NewWriter(w)
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		if column != CSVBoundary && column != CSVEquivalent {
+			if _, err := csvField(&commitRecord{}, column); err != nil {
+				return err
+			}
+		}
+		header[i] = string(column)
+	}
+
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, commit := range commits {
+		record := newCommitRecord(commit)
+		row := make([]string, len(columns))
+
+		for i, column := range columns {
+			switch column {
+			case CSVBoundary:
+				row[i] = strconv.FormatBool(record.Boundary)
+			case CSVEquivalent:
+				row[i] = strconv.FormatBool(record.Equivalent)
+			default:
+				field, err := csvField(record, column)
+				if err != nil {
+					return err
+				}
+				row[i] = *field
+			}
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// DecodeCSV reads commits written by EncodeCSV, the columns are given by the header
+// fields of the columns not in CSV are left empty
+func DecodeCSV(r io.Reader) ([]*Commit, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []*Commit{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Check columns before reading rows
+	for _, column := range header {
+		switch CSVColumn(column) {
+		case CSVBoundary, CSVEquivalent:
+		default:
+			if _, err := csvField(&commitRecord{}, CSVColumn(column)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	commits := []*Commit{}
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return commits, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		record := &commitRecord{}
+
+		for i, column := range header {
+			switch CSVColumn(column) {
+			case CSVBoundary:
+				record.Boundary, err = parseBool(row[i])
+			case CSVEquivalent:
+				record.Equivalent, err = parseBool(row[i])
+			default:
+				var field *string
+				field, err = csvField(record, CSVColumn(column))
+				if err == nil {
+					*field = row[i]
+				}
+			}
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		commit, err := record.commit()
+		if err != nil {
+			return nil, err
+		}
+
+		commits = append(commits, commit)
+	}
+}
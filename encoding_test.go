@@ -0,0 +1,161 @@
+package gitlog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodingCommit() *Commit {
+	date := time.Date(2018, 1, 28, 11, 32, 41, 0, time.FixedZone("", 9*60*60))
+
+	return &Commit{
+		Hash:      &Hash{Long: "51064a83516c60fdffd99a7d605d168298d91464", Short: "51064a8"},
+		Tree:      &Tree{Long: "4b825dc642cb6eb9a060e54bf8d69288fbee4904", Short: "4b825dc"},
+		Author:    &Author{Name: "tsuyoshiwada", Email: "mail@example.com", Date: date},
+		Committer: &Committer{Name: "committer", Email: "committer@example.com", Date: date.Add(time.Hour), RawName: "raw", RawEmail: "raw@example.com"},
+		Tag:       &Tag{Name: "v1.0.0", Date: date},
+		Subject:   "chore(*): Has commit body",
+		Body:      "multiline\n\"quoted\", comma",
+		Side:      SideLeft,
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	assert.Nil(EncodeJSON(&buf, []*Commit{encodingCommit()}))
+	assert.JSONEq(`[{
+		"hash": {"long": "51064a83516c60fdffd99a7d605d168298d91464", "short": "51064a8"},
+		"tree": {"long": "4b825dc642cb6eb9a060e54bf8d69288fbee4904", "short": "4b825dc"},
+		"author": {"name": "tsuyoshiwada", "email": "mail@example.com", "date": "2018-01-28T11:32:41+09:00"},
+		"committer": {"name": "committer", "email": "committer@example.com", "date": "2018-01-28T12:32:41+09:00", "raw_name": "raw", "raw_email": "raw@example.com"},
+		"tag": "v1.0.0",
+		"subject": "chore(*): Has commit body",
+		"body": "multiline\n\"quoted\", comma",
+		"side": "left"
+	}]`, buf.String())
+
+	commits, err := DecodeJSON(&buf)
+	assert.Nil(err)
+	assert.Equal(1, len(commits))
+	assertSameCommit(t, encodingCommit(), commits[0])
+
+	_, err = DecodeJSON(strings.NewReader(`[{"side": "up"}]`))
+	assert.NotNil(err)
+
+	_, err = DecodeJSON(strings.NewReader(`[{"author": {"date": "yesterday"}}]`))
+	assert.NotNil(err)
+}
+
+func TestEncodeNDJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	expected, _ := gitLog.Log(nil, nil)
+
+	var buf bytes.Buffer
+	assert.Nil(EncodeNDJSON(&buf, expected))
+	assert.Equal(7, strings.Count(buf.String(), "\n"))
+
+	commits, err := DecodeNDJSON(&buf)
+	assert.Nil(err)
+	assert.Equal(len(expected), len(commits))
+	for i := range commits {
+		assertSameCommit(t, expected[i], commits[i])
+	}
+
+	decoder := NewNDJSONDecoder(strings.NewReader(`{"subject": "foo"}` + "\n\n" + `{"subject": `))
+	commit, err := decoder.Decode()
+	assert.Nil(err)
+	assert.Equal("foo", commit.Subject)
+	assert.NotNil(commit.Hash)
+
+	_, err = decoder.Decode()
+	assert.Contains(err.Error(), "line 3")
+}
+
+func TestEncodeCSV(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	assert.Nil(EncodeCSV(&buf, []*Commit{encodingCommit()}, []CSVColumn{CSVShortHash, CSVAuthorDate, CSVBody, CSVSide, CSVBoundary}))
+	assert.Equal(`short_hash,author_date,body,side,boundary
+51064a8,2018-01-28T11:32:41+09:00,"multiline
+""quoted"", comma",left,false
+`, buf.String())
+
+	commits, err := DecodeCSV(&buf)
+	assert.Nil(err)
+	assert.Equal(1, len(commits))
+	assert.Equal("51064a8", commits[0].Hash.Short)
+	assert.Equal("", commits[0].Hash.Long)
+	assert.Equal(encodingCommit().Body, commits[0].Body)
+	assert.Equal(SideLeft, commits[0].Side)
+	assert.True(encodingCommit().Author.Date.Equal(commits[0].Author.Date))
+	assert.True(commits[0].Committer.Date.IsZero())
+
+	// all columns
+	columns := []CSVColumn{
+		CSVHash, CSVShortHash, CSVTree, CSVShortTree,
+		CSVAuthorName, CSVAuthorEmail, CSVAuthorDate, CSVAuthorRawName, CSVAuthorRawEmail,
+		CSVCommitterName, CSVCommitterEmail, CSVCommitterDate, CSVCommitterRawName, CSVCommitterRawEmail,
+		CSVTag, CSVSubject, CSVBody, CSVBoundary, CSVSide, CSVEquivalent,
+	}
+
+	buf.Reset()
+	assert.Nil(EncodeCSV(&buf, []*Commit{encodingCommit()}, columns))
+	commits, err = DecodeCSV(&buf)
+	assert.Nil(err)
+	assertSameCommit(t, encodingCommit(), commits[0])
+
+	// default columns
+	buf.Reset()
+	assert.Nil(EncodeCSV(&buf, []*Commit{encodingCommit()}, nil))
+	assert.True(strings.HasPrefix(buf.String(), "hash,short_hash,author_name,author_email,author_date,committer_name,committer_email,committer_date,tag,subject,body\n"))
+
+	assert.NotNil(EncodeCSV(&buf, []*Commit{encodingCommit()}, []CSVColumn{"unknown"}))
+
+	// columns are checked before the header is written
+	buf.Reset()
+	assert.NotNil(EncodeCSV(&buf, []*Commit{}, []CSVColumn{CSVHash, "unknown"}))
+	assert.Equal("", buf.String())
+
+	_, err = DecodeCSV(strings.NewReader("hash,unknown\n"))
+	assert.NotNil(err)
+
+	_, err = DecodeCSV(strings.NewReader("boundary\nmaybe\n"))
+	assert.NotNil(err)
+
+	commits, err = DecodeCSV(strings.NewReader(""))
+	assert.Nil(err)
+	assert.Equal(0, len(commits))
+}
+
+// assertSameCommit compares commits, dates are compared by the instant
+func assertSameCommit(t *testing.T, expected, actual *Commit) {
+	assert := assert.New(t)
+
+	assert.True(expected.Author.Date.Equal(actual.Author.Date))
+	assert.True(expected.Committer.Date.Equal(actual.Committer.Date))
+	assert.True(expected.Tag.Date.Equal(actual.Tag.Date))
+
+	e, a := *expected, *actual
+	ea, aa := *e.Author, *a.Author
+	ec, ac := *e.Committer, *a.Committer
+	et, at := *e.Tag, *a.Tag
+	ea.Date, aa.Date, ec.Date, ac.Date, et.Date, at.Date = time.Time{}, time.Time{}, time.Time{}, time.Time{}, time.Time{}, time.Time{}
+	e.Author, a.Author, e.Committer, a.Committer, e.Tag, a.Tag = &ea, &aa, &ec, &ac, &et, &at
+
+	assert.Equal(&e, &a)
+}
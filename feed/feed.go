@@ -0,0 +1,286 @@
+// Package feed is providing a means to publish git-log as Atom 1.0 and RSS 2.0 feeds.
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"text/template"
+	"time"
+
+	gitlog "github.com/tsuyoshiwada/go-gitlog"
+)
+
+// Options of feed
+type Options struct {
+	Title       string // required
+	Link        string // URL of the site, required for RSS
+	ID          string // ID of the feed, default Link
+	Self        string // URL of the feed itself, the `rel="self"` link of Atom
+	Description string // subtitle of Atom, description of RSS (default Title)
+	CommitURL   string // text/template of the entry link executed with *gitlog.Commit, e.g. `https://github.com/o/r/commit/{{ .Hash.Long }}`
+}
+
+// EntryID returns the ID of the entry derived from the feed ID and the commit hash
+// the feed ID is included, so the entries of the same commit in feeds of different repositories are distinct
+func EntryID(feedID string, commit *gitlog.Commit) string {
+	return feedID + "#" + commit.Hash.Long
+}
+
+type atomFeed struct {
+	XMLName  xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string       `xml:"title"`
+	ID       string       `xml:"id"`
+	Updated  string       `xml:"updated"`
+	Subtitle string       `xml:"subtitle,omitempty"`
+	Links    []*atomLink  `xml:"link"`
+	Entries  []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email,omitempty"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title     string       `xml:"title"`
+	ID        string       `xml:"id"`
+	Updated   string       `xml:"updated"`
+	Published string       `xml:"published"`
+	Author    *atomPerson  `xml:"author"`
+	Link      *atomLink    `xml:"link,omitempty"`
+	Content   *atomContent `xml:"content"`
+}
+
+type rss struct {
+	XMLName xml.Name    `xml:"rss"`
+	Version string      `xml:"version,attr"`
+	Channel *rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description"`
+	Author      string   `xml:"author,omitempty"`
+	GUID        *rssGUID `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+// Atom writes the commits as Atom 1.0
+func Atom(w io.Writer, commits []*gitlog.Commit, options *Options) error {
+	opts, link, err := prepare(options)
+	if err != nil {
+		return err
+	}
+
+	id := feedID(opts)
+	if id == "" {
+		return errors.New("\"ID\" or \"Link\" is required for Atom")
+	}
+
+	feed := &atomFeed{
+		Title:    opts.Title,
+		ID:       id,
+		Updated:  updated(commits).Format(time.RFC3339),
+		Subtitle: opts.Description,
+		Entries:  []*atomEntry{},
+	}
+
+	if opts.Link != "" {
+		feed.Links = append(feed.Links, &atomLink{Href: opts.Link})
+	}
+
+	if opts.Self != "" {
+		feed.Links = append(feed.Links, &atomLink{Href: opts.Self, Rel: "self"})
+	}
+
+	for _, commit := range commits {
+		href, err := link(commit)
+		if err != nil {
+			return err
+		}
+
+		entry := &atomEntry{
+			Title:     commit.Subject,
+			ID:        EntryID(id, commit),
+			Updated:   commit.Committer.Date.Format(time.RFC3339),
+			Published: commit.Author.Date.Format(time.RFC3339),
+			Author: &atomPerson{
+				Name:  commit.Author.Name,
+				Email: commit.Author.Email,
+			},
+			Content: &atomContent{
+				Type: "text",
+				Body: content(commit),
+			},
+		}
+
+		if href != "" {
+			entry.Link = &atomLink{Href: href, Rel: "alternate"}
+		}
+
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return encode(w, feed)
+}
+
+// RSS writes the commits as RSS 2.0
+func RSS(w io.Writer, commits []*gitlog.Commit, options *Options) error {
+	opts, link, err := prepare(options)
+	if err != nil {
+		return err
+	}
+
+	if opts.Link == "" {
+		return errors.New("\"Link\" is required for RSS")
+	}
+
+	channel := &rssChannel{
+		Title:       opts.Title,
+		Link:        opts.Link,
+		Description: opts.Description,
+		Items:       []*rssItem{},
+	}
+
+	if channel.Description == "" {
+		channel.Description = opts.Title
+	}
+
+	if len(commits) > 0 {
+		channel.LastBuildDate = updated(commits).Format(time.RFC1123Z)
+	}
+
+	for _, commit := range commits {
+		href, err := link(commit)
+		if err != nil {
+			return err
+		}
+
+		item := &rssItem{
+			Title:       commit.Subject,
+			Link:        href,
+			Description: content(commit),
+			GUID: &rssGUID{
+				IsPermaLink: "false",
+				Value:       EntryID(feedID(opts), commit),
+			},
+			PubDate: commit.Committer.Date.Format(time.RFC1123Z),
+		}
+
+		if commit.Author.Email != "" {
+			item.Author = commit.Author.Email + " (" + commit.Author.Name + ")"
+		}
+
+		channel.Items = append(channel.Items, item)
+	}
+
+	return encode(w, &rss{
+		Version: "2.0",
+		Channel: channel,
+	})
+}
+
+// prepare checks options and returns the function building entry link
+func prepare(options *Options) (*Options, func(*gitlog.Commit) (string, error), error) {
+	if options == nil || options.Title == "" {
+		return nil, nil, errors.New("\"Title\" is required")
+	}
+
+	link := func(*gitlog.Commit) (string, error) {
+		return "", nil
+	}
+
+	if options.CommitURL != "" {
+		tmpl, err := template.New("url").Parse(options.CommitURL)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		link = func(commit *gitlog.Commit) (string, error) {
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, commit); err != nil {
+				return "", err
+			}
+			return buf.String(), nil
+		}
+	}
+
+	return options, link, nil
+}
+
+// feedID returns ID, or Link if ID is empty
+func feedID(options *Options) string {
+	if options.ID != "" {
+		return options.ID
+	}
+	return options.Link
+}
+
+// updated returns the latest committer date, the current time if there is no commit
+func updated(commits []*gitlog.Commit) time.Time {
+	var latest time.Time
+
+	for _, commit := range commits {
+		if commit.Committer.Date.After(latest) {
+			latest = commit.Committer.Date
+		}
+	}
+
+	if latest.IsZero() {
+		return timeNow()
+	}
+
+	return latest
+}
+
+var timeNow = time.Now
+
+// content returns the body, or the subject if the body is empty
+func content(commit *gitlog.Commit) string {
+	if commit.Body != "" {
+		return commit.Body
+	}
+	return commit.Subject
+}
+
+func encode(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,142 @@
+package feed
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	gitlog "github.com/tsuyoshiwada/go-gitlog"
+)
+
+func commits() []*gitlog.Commit {
+	date := time.Date(2018, 1, 28, 11, 32, 41, 0, time.UTC)
+
+	return []*gitlog.Commit{
+		{
+			Hash:      &gitlog.Hash{Long: "51064a83516c60fdffd99a7d605d168298d91464", Short: "51064a8"},
+			Author:    &gitlog.Author{Name: "tsuyoshiwada", Email: "mail@example.com", Date: date},
+			Committer: &gitlog.Committer{Name: "committer", Email: "committer@example.com", Date: date.Add(time.Hour)},
+			Subject:   "feat(parser): Add <foo> & bar",
+			Body:      "This is body",
+		},
+		{
+			Hash:      &gitlog.Hash{Long: "806512fe97c9c3397b7ed30c0b4076032112f697", Short: "806512f"},
+			Author:    &gitlog.Author{Name: "tsuyoshiwada", Email: "mail@example.com", Date: date.Add(-time.Hour)},
+			Committer: &gitlog.Committer{Name: "tsuyoshiwada", Email: "mail@example.com", Date: date.Add(-time.Hour)},
+			Subject:   "chore(*): Initial commit",
+		},
+	}
+}
+
+var options = &Options{
+	Title:       "go-gitlog",
+	Link:        "https://github.com/tsuyoshiwada/go-gitlog",
+	Description: "Commits of go-gitlog",
+	Self:        "https://example.com/go-gitlog.atom",
+	CommitURL:   "https://github.com/tsuyoshiwada/go-gitlog/commit/{{ .Hash.Long }}",
+}
+
+func TestAtom(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	assert.Nil(Atom(&buf, commits(), options))
+	assert.Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>go-gitlog</title>
+  <id>https://github.com/tsuyoshiwada/go-gitlog</id>
+  <updated>2018-01-28T12:32:41Z</updated>
+  <subtitle>Commits of go-gitlog</subtitle>
+  <link href="https://github.com/tsuyoshiwada/go-gitlog"></link>
+  <link href="https://example.com/go-gitlog.atom" rel="self"></link>
+  <entry>
+    <title>feat(parser): Add &lt;foo&gt; &amp; bar</title>
+    <id>https://github.com/tsuyoshiwada/go-gitlog#51064a83516c60fdffd99a7d605d168298d91464</id>
+    <updated>2018-01-28T12:32:41Z</updated>
+    <published>2018-01-28T11:32:41Z</published>
+    <author>
+      <name>tsuyoshiwada</name>
+      <email>mail@example.com</email>
+    </author>
+    <link href="https://github.com/tsuyoshiwada/go-gitlog/commit/51064a83516c60fdffd99a7d605d168298d91464" rel="alternate"></link>
+    <content type="text">This is body</content>
+  </entry>
+  <entry>
+    <title>chore(*): Initial commit</title>
+    <id>https://github.com/tsuyoshiwada/go-gitlog#806512fe97c9c3397b7ed30c0b4076032112f697</id>
+    <updated>2018-01-28T10:32:41Z</updated>
+    <published>2018-01-28T10:32:41Z</published>
+    <author>
+      <name>tsuyoshiwada</name>
+      <email>mail@example.com</email>
+    </author>
+    <link href="https://github.com/tsuyoshiwada/go-gitlog/commit/806512fe97c9c3397b7ed30c0b4076032112f697" rel="alternate"></link>
+    <content type="text">chore(*): Initial commit</content>
+  </entry>
+</feed>
+`, buf.String())
+}
+
+func TestRSS(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	assert.Nil(RSS(&buf, commits()[:1], options))
+	assert.Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>go-gitlog</title>
+    <link>https://github.com/tsuyoshiwada/go-gitlog</link>
+    <description>Commits of go-gitlog</description>
+    <lastBuildDate>Sun, 28 Jan 2018 12:32:41 +0000</lastBuildDate>
+    <item>
+      <title>feat(parser): Add &lt;foo&gt; &amp; bar</title>
+      <link>https://github.com/tsuyoshiwada/go-gitlog/commit/51064a83516c60fdffd99a7d605d168298d91464</link>
+      <description>This is body</description>
+      <author>mail@example.com (tsuyoshiwada)</author>
+      <guid isPermaLink="false">https://github.com/tsuyoshiwada/go-gitlog#51064a83516c60fdffd99a7d605d168298d91464</guid>
+      <pubDate>Sun, 28 Jan 2018 12:32:41 +0000</pubDate>
+    </item>
+  </channel>
+</rss>
+`, buf.String())
+}
+
+func TestOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	now := timeNow
+	timeNow = func() time.Time {
+		return time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC)
+	}
+	defer func() {
+		timeNow = now
+	}()
+
+	var buf bytes.Buffer
+
+	// without link
+	assert.Nil(Atom(&buf, []*gitlog.Commit{}, &Options{Title: "empty", ID: "urn:example:feed"}))
+	assert.Contains(buf.String(), "<updated>2018-02-01T00:00:00Z</updated>")
+	assert.NotContains(buf.String(), "<link")
+
+	buf.Reset()
+	assert.Nil(RSS(&buf, commits(), &Options{Title: "no description", Link: "https://example.com"}))
+	assert.Contains(buf.String(), "<description>no description</description>")
+	assert.NotContains(buf.String(), "<link>https://example.com/")
+
+	assert.NotNil(Atom(&buf, commits(), nil))
+	assert.NotNil(RSS(&buf, commits(), &Options{}))
+	assert.NotNil(Atom(&buf, commits(), &Options{Title: "no id"}))
+	assert.NotNil(RSS(&buf, commits(), &Options{Title: "no link"}))
+	assert.NotNil(RSS(&buf, commits(), &Options{Title: "title", Link: "https://example.com", CommitURL: "{{ .Foo "}))
+	assert.NotNil(RSS(&buf, commits(), &Options{Title: "title", Link: "https://example.com", CommitURL: "{{ .Foo }}"}))
+
+	assert.Equal("urn:example:feed#806512fe97c9c3397b7ed30c0b4076032112f697", EntryID("urn:example:feed", commits()[1]))
+
+	// entries of the same commit differ between feeds
+	buf.Reset()
+	assert.Nil(Atom(&buf, commits(), &Options{Title: "fork", ID: "urn:example:fork"}))
+	assert.Contains(buf.String(), "<id>urn:example:fork#806512fe97c9c3397b7ed30c0b4076032112f697</id>")
+}
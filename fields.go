@@ -16,6 +16,24 @@ type Tree struct {
 
 // Author of commit
 type Author struct {
+	Name     string
+	Email    string
+	Date     time.Time
+	RawName  string // name before mailmap is applied, empty if mailmap is not used
+	RawEmail string // email before mailmap is applied, empty if mailmap is not used
+}
+
+// TagType of tag
+type TagType int
+
+// List of TagType, the zero value is used for Tag of Commit
+const (
+	TagLightweight TagType = iota + 1
+	TagAnnotated
+)
+
+// Tagger of annotated tag
+type Tagger struct {
 	Name  string
 	Email string
 	Date  time.Time
@@ -23,24 +41,44 @@ type Author struct {
 
 // Tag of commit
 type Tag struct {
-	Name string
-	Date time.Time
+	Name    string
+	Date    time.Time
+	Type    TagType  // following fields are filled by Tags()
+	Commit  *Hash    // target commit
+	Tagger  *Tagger  // nil for lightweight tag
+	Message string   // empty for lightweight tag
+	Version *Version // nil if the name is not semantic version
 }
 
 // Committer of commit
 type Committer struct {
-	Name  string
-	Email string
-	Date  time.Time
+	Name     string
+	Email    string
+	Date     time.Time
+	RawName  string // name before mailmap is applied, empty if mailmap is not used
+	RawEmail string // email before mailmap is applied, empty if mailmap is not used
 }
 
+// Side of the symmetric difference to which the commit belongs
+type Side int
+
+// List of Side
+const (
+	SideNone  Side = iota // not a symmetric difference
+	SideLeft              // reachable only from the left side (`<`)
+	SideRight             // reachable only from the right side (`>`)
+)
+
 // Commit data
 type Commit struct {
-	Hash      *Hash
-	Tree      *Tree
-	Author    *Author
-	Committer *Committer
-	Tag       *Tag
-	Subject   string
-	Body      string
+	Hash       *Hash
+	Tree       *Tree
+	Author     *Author
+	Committer  *Committer
+	Tag        *Tag
+	Subject    string
+	Body       string
+	Boundary   bool // true if the commit is a boundary commit (`--boundary`)
+	Side       Side // side of the symmetric difference (`--left-right`)
+	Equivalent bool // true if an equivalent patch exists on the other side (`--cherry-mark`)
 }
@@ -0,0 +1,233 @@
+package gitlog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Format of Formatter
+type Format struct {
+	Template  string // text/template executed with *Commit
+	Separator string // written between commits
+}
+
+// gitDateLayout is the default date format of git
+const gitDateLayout = "Mon Jan 2 15:04:05 2006 -0700"
+
+// FormatOneline mimics `git log --pretty=oneline`
+func FormatOneline() *Format {
+	return &Format{
+		Template: "{{ short .Hash }} {{ .Subject }}\n",
+	}
+}
+
+// FormatMedium mimics `git log --pretty=medium --date=local`, use `in` in your template for the other time zone
+func FormatMedium() *Format {
+	return &Format{
+		Template: `commit {{ .Hash.Long }}
+Author: {{ .Author.Name }} <{{ .Author.Email }}>
+Date:   {{ date "` + gitDateLayout + `" .Author.Date }}
+
+{{ indent 4 .Subject }}
+{{ if .Body }}
+{{ indent 4 .Body }}
+{{ end }}`,
+		Separator: "\n",
+	}
+}
+
+// FormatFuller mimics `git log --pretty=fuller --date=local`, use `in` in your template for the other time zone
+func FormatFuller() *Format {
+	return &Format{
+		Template: `commit {{ .Hash.Long }}
+Author:     {{ .Author.Name }} <{{ .Author.Email }}>
+AuthorDate: {{ date "` + gitDateLayout + `" .Author.Date }}
+Commit:     {{ .Committer.Name }} <{{ .Committer.Email }}>
+CommitDate: {{ date "` + gitDateLayout + `" .Committer.Date }}
+
+{{ indent 4 .Subject }}
+{{ if .Body }}
+{{ indent 4 .Body }}
+{{ end }}`,
+		Separator: "\n",
+	}
+}
+
+// Formatter renders commits with text/template
+// the following functions are available in the template
+//
+//	short .Hash                     abbreviated hash of *Hash, *Tree or string
+//	relative .Author.Date           relative date such as "3 days ago"
+//	truncate 50 .Subject            truncate to 50 characters with "...", negative is 0
+//	date "2006-01-02" .Author.Date  format date with the layout of time package
+//	in "Asia/Tokyo" .Author.Date    date in the time zone, "Local" and "UTC" are also available
+//	indent 4 .Body                  indent non-empty lines with spaces
+type Formatter struct {
+	template  *template.Template
+	separator string
+}
+
+// NewFormatter returns Formatter of format
+func NewFormatter(format *Format) (*Formatter, error) {
+	if format == nil {
+		format = FormatMedium()
+	}
+
+	tmpl, err := template.New("format").Funcs(template.FuncMap{
+		"short":    formatShort,
+		"relative": formatRelative,
+		"truncate": formatTruncate,
+		"date":     formatLayout,
+		"in":       formatIn,
+		"indent":   formatIndent,
+	}).Parse(format.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Formatter{
+		template:  tmpl,
+		separator: format.Separator,
+	}, nil
+}
+
+// Format writes commits
+func (f *Formatter) Format(w io.Writer, commits []*Commit) error {
+	for i, commit := range commits {
+		if i > 0 && f.separator != "" {
+			if _, err := io.WriteString(w, f.separator); err != nil {
+				return err
+			}
+		}
+
+		if err := f.template.Execute(w, commit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FormatCommit returns the commit rendered as string
+func (f *Formatter) FormatCommit(commit *Commit) (string, error) {
+	var buf bytes.Buffer
+
+	if err := f.template.Execute(&buf, commit); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func formatShort(v interface{}) (string, error) {
+	var long, short string
+
+	switch hash := v.(type) {
+	case *Hash:
+		if hash != nil {
+			long, short = hash.Long, hash.Short
+		}
+	case *Tree:
+		if hash != nil {
+			long, short = hash.Long, hash.Short
+		}
+	case string:
+		long = hash
+	default:
+		return "", fmt.Errorf("short: unsupported type %T", v)
+	}
+
+	if short != "" {
+		return short, nil
+	}
+
+	if len(long) > 7 {
+		return long[:7], nil
+	}
+
+	return long, nil
+}
+
+func formatRelative(t time.Time) string {
+	d := timeNow().Sub(t)
+
+	if d < 0 {
+		return "in the future"
+	}
+
+	seconds := int(d.Seconds())
+
+	units := []struct {
+		name    string
+		seconds int
+		limit   int
+	}{
+		{"second", 1, 90},
+		{"minute", 60, 90 * 60},
+		{"hour", 60 * 60, 36 * 60 * 60},
+		{"day", 24 * 60 * 60, 14 * 24 * 60 * 60},
+		{"week", 7 * 24 * 60 * 60, 60 * 24 * 60 * 60},
+		{"month", 30 * 24 * 60 * 60, 365 * 24 * 60 * 60},
+	}
+
+	for _, unit := range units {
+		if seconds < unit.limit {
+			return plural(seconds/unit.seconds, unit.name) + " ago"
+		}
+	}
+
+	return plural(seconds/(365*24*60*60), "year") + " ago"
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+func formatTruncate(n int, s string) string {
+	if n < 0 {
+		n = 0
+	}
+
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+
+	if n <= 3 {
+		return string(runes[:n])
+	}
+
+	return string(runes[:n-3]) + "..."
+}
+
+func formatLayout(layout string, t time.Time) string {
+	return t.Format(layout)
+}
+
+func formatIn(zone string, t time.Time) (time.Time, error) {
+	location, err := time.LoadLocation(zone)
+	if err != nil {
+		return t, err
+	}
+	return t.In(location), nil
+}
+
+func formatIndent(n int, s string) string {
+	prefix := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
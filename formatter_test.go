@@ -0,0 +1,166 @@
+package gitlog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func formatterCommits() []*Commit {
+	date := time.Date(2018, 1, 28, 11, 32, 41, 0, time.UTC)
+
+	return []*Commit{
+		{
+			Hash:      &Hash{Long: "51064a83516c60fdffd99a7d605d168298d91464", Short: "51064a8"},
+			Author:    &Author{Name: "tsuyoshiwada", Email: "mail@example.com", Date: date},
+			Committer: &Committer{Name: "committer", Email: "committer@example.com", Date: date.Add(time.Hour)},
+			Subject:   "chore(*): Has commit body",
+			Body:      "This is body\n\nmultiline",
+		},
+		{
+			Hash:      &Hash{Long: "806512fe97c9c3397b7ed30c0b4076032112f697", Short: "806512f"},
+			Author:    &Author{Name: "tsuyoshiwada", Email: "mail@example.com", Date: date.Add(-time.Hour)},
+			Committer: &Committer{Name: "tsuyoshiwada", Email: "mail@example.com", Date: date.Add(-time.Hour)},
+			Subject:   "chore(*): Initial commit",
+		},
+	}
+}
+
+func TestFormatterPresets(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+
+	formatter, err := NewFormatter(FormatOneline())
+	assert.Nil(err)
+	assert.Nil(formatter.Format(&buf, formatterCommits()))
+	assert.Equal(`51064a8 chore(*): Has commit body
+806512f chore(*): Initial commit
+`, buf.String())
+
+	buf.Reset()
+	formatter, err = NewFormatter(nil)
+	assert.Nil(err)
+	assert.Nil(formatter.Format(&buf, formatterCommits()))
+	assert.Equal(`commit 51064a83516c60fdffd99a7d605d168298d91464
+Author: tsuyoshiwada <mail@example.com>
+Date:   Sun Jan 28 11:32:41 2018 +0000
+
+    chore(*): Has commit body
+
+    This is body
+
+    multiline
+
+commit 806512fe97c9c3397b7ed30c0b4076032112f697
+Author: tsuyoshiwada <mail@example.com>
+Date:   Sun Jan 28 10:32:41 2018 +0000
+
+    chore(*): Initial commit
+`, buf.String())
+
+	buf.Reset()
+	formatter, err = NewFormatter(FormatFuller())
+	assert.Nil(err)
+	assert.Nil(formatter.Format(&buf, formatterCommits()[:1]))
+	assert.Equal(`commit 51064a83516c60fdffd99a7d605d168298d91464
+Author:     tsuyoshiwada <mail@example.com>
+AuthorDate: Sun Jan 28 11:32:41 2018 +0000
+Commit:     committer <committer@example.com>
+CommitDate: Sun Jan 28 12:32:41 2018 +0000
+
+    chore(*): Has commit body
+
+    This is body
+
+    multiline
+`, buf.String())
+}
+
+func TestFormatterTimeZone(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	git("-C", ".tmp", "commit", "--allow-empty", "--date", "2018-01-28T20:32:41+09:00", "-m", "chore(*): Zone")
+
+	commits, err := NewClient(&Config{Path: ".tmp"}).Log(&Rev{Ref: "HEAD"}, nil)
+	assert.Nil(err)
+	assert.Equal(time.Local, commits[0].Author.Date.Location())
+
+	// the dates of Log are local, the time zone is converted by the formatter
+	formatter, _ := NewFormatter(&Format{Template: `{{ date "` + gitDateLayout + `" (in "Asia/Tokyo" .Author.Date) }}`})
+	out, err := formatter.FormatCommit(commits[0])
+	assert.Nil(err)
+	assert.Equal("Sun Jan 28 20:32:41 2018 +0900", out)
+}
+
+func TestFormatterFuncs(t *testing.T) {
+	assert := assert.New(t)
+
+	now := timeNow
+	timeNow = func() time.Time {
+		return time.Date(2018, 2, 4, 11, 32, 41, 0, time.UTC)
+	}
+	defer func() {
+		timeNow = now
+	}()
+
+	commit := formatterCommits()[0]
+
+	table := []struct {
+		template string
+		expect   string
+	}{
+		{`{{ short .Hash }}`, "51064a8"},
+		{`{{ short .Hash.Long }}`, "51064a8"},
+		{`{{ short "abc" }}`, "abc"},
+		{`{{ relative .Author.Date }}`, "7 days ago"},
+		{`{{ relative .Committer.Date }}`, "6 days ago"},
+		{`{{ truncate 10 .Subject }}`, "chore(*..."},
+		{`{{ truncate 100 .Subject }}`, "chore(*): Has commit body"},
+		{`{{ truncate -1 .Subject }}`, ""},
+		{`{{ date "2006-01-02 15:04" .Author.Date }}`, "2018-01-28 11:32"},
+		{`{{ date "2006-01-02 15:04 -0700" (in "Asia/Tokyo" .Author.Date) }}`, "2018-01-28 20:32 +0900"},
+		{`{{ indent 2 .Body }}`, "  This is body\n\n  multiline"},
+	}
+
+	for _, test := range table {
+		formatter, err := NewFormatter(&Format{Template: test.template})
+		assert.Nil(err)
+
+		out, err := formatter.FormatCommit(commit)
+		assert.Nil(err)
+		assert.Equal(test.expect, out, test.template)
+	}
+
+	for _, test := range []struct {
+		duration time.Duration
+		expect   string
+	}{
+		{30 * time.Second, "30 seconds ago"},
+		{time.Minute, "60 seconds ago"},
+		{5 * time.Minute, "5 minutes ago"},
+		{3 * time.Hour, "3 hours ago"},
+		{45 * 24 * time.Hour, "6 weeks ago"},
+		{100 * 24 * time.Hour, "3 months ago"},
+		{800 * 24 * time.Hour, "2 years ago"},
+		{-time.Hour, "in the future"},
+	} {
+		assert.Equal(test.expect, formatRelative(timeNow().Add(-test.duration)))
+	}
+
+	formatter, _ := NewFormatter(&Format{Template: `{{ in "Unknown/Zone" .Author.Date }}`})
+	_, err := formatter.FormatCommit(commit)
+	assert.NotNil(err)
+
+	formatter, _ = NewFormatter(&Format{Template: `{{ short .Author }}`})
+	_, err = formatter.FormatCommit(commit)
+	assert.NotNil(err)
+
+	_, err = NewFormatter(&Format{Template: `{{ .Foo `})
+	assert.NotNil(err)
+}
@@ -2,8 +2,16 @@
 package gitlog
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	gitcmd "github.com/tsuyoshiwada/go-gitcmd"
 )
@@ -16,6 +24,11 @@ const (
 	subjectField   = "SUBJECT"
 	bodyField      = "BODY"
 	tagField       = "TAG"
+	markField      = "MARK"
+	statsField     = "STATS"
+
+	rawAuthorField    = "RAW_AUTHOR"
+	rawCommitterField = "RAW_COMMITTER"
 
 	hashFormat      = hashField + ":%H %h"
 	treeFormat      = treeField + ":%T %t"
@@ -24,6 +37,12 @@ const (
 	subjectFormat   = subjectField + ":%s"
 	bodyFormat      = bodyField + ":%b"
 	tagFormat       = tagField + ":%D"
+	markFormat      = markField + ":%m"
+
+	mailmapAuthorFormat    = authorField + ":%aN<%aE>[%at]"
+	mailmapCommitterFormat = committerField + ":%cN<%cE>[%ct]"
+	rawAuthorFormat        = rawAuthorField + ":%an<%ae>[%at]"
+	rawCommitterFormat     = rawCommitterField + ":%cn<%ce>[%ct]"
 
 	separator = "@@__GIT_LOG_SEPARATOR__@@"
 	delimiter = "@@__GIT_LOG_DELIMITER__@@"
@@ -34,6 +53,20 @@ const (
 		authorFormat + delimiter +
 		committerFormat + delimiter +
 		tagFormat + delimiter +
+		markFormat + delimiter +
+		subjectFormat + delimiter +
+		bodyFormat
+
+	// logFormat with the identities mapped by .mailmap
+	mailmapLogFormat = separator +
+		hashFormat + delimiter +
+		treeFormat + delimiter +
+		mailmapAuthorFormat + delimiter +
+		rawAuthorFormat + delimiter +
+		mailmapCommitterFormat + delimiter +
+		rawCommitterFormat + delimiter +
+		tagFormat + delimiter +
+		markFormat + delimiter +
 		subjectFormat + delimiter +
 		bodyFormat
 )
@@ -41,14 +74,7 @@ const (
 // Config for getting git-log
 type Config struct {
 	Bin  string // default "git"
-	Path string // default "."
-}
-
-// Params for getting git-log
-type Params struct {
-	MergesOnly   bool
-	IgnoreMerges bool
-	Reverse      bool
+	Path string // default ".", relative path is resolved when New is called
 }
 
 // GitLog is an interface for git-log acquisition
@@ -56,14 +82,21 @@ type GitLog interface {
 	Log(RevArgs, *Params) ([]*Commit, error)
 }
 
-type gitLogImpl struct {
+// Client implements GitLog, the other queries of the repository are provided as its methods
+type Client struct {
 	client gitcmd.Client
 	parser *parser
 	config *Config
+	dir    string // absolute path of the repository
 }
 
 // New GitLog interface
 func New(config *Config) GitLog {
+	return NewClient(config)
+}
+
+// NewClient returns Client of the repository
+func NewClient(config *Config) *Client {
 	bin := "git"
 	path := "path"
 
@@ -77,7 +110,12 @@ func New(config *Config) GitLog {
 		}
 	}
 
-	return &gitLogImpl{
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		dir = path
+	}
+
+	return &Client{
 		client: gitcmd.New(&gitcmd.Config{
 			Bin: bin,
 		}),
@@ -86,41 +124,98 @@ func New(config *Config) GitLog {
 			Bin:  bin,
 			Path: path,
 		},
+		dir: dir,
 	}
 }
 
-// workingdir is go to the temporary working directory
-func (gitLog *gitLogImpl) workdir() (func() error, error) {
-	cwd, err := filepath.Abs(".")
+// GitNotFoundError is returned when the git command can not be executed
+type GitNotFoundError struct {
+	Bin string
+}
+
+func (e *GitNotFoundError) Error() string {
+	return fmt.Sprintf("\"%s\" does not exists", e.Bin)
+}
+
+// NotRepositoryError is returned when Path is not inside a git work tree
+type NotRepositoryError struct {
+	Path string
+	Err  error // underlying error
+}
+
+func (e *NotRepositoryError) Error() string {
+	return fmt.Sprintf("\"%s\" is not a git repository: %s", e.Path, e.Err)
+}
 
-	back := func() error {
-		return os.Chdir(cwd)
+// prepare checks the git command and the repository
+func (gitLog *Client) prepare() error {
+	// Can execute the git command?
+	if err := gitLog.client.CanExec(); err != nil {
+		return &GitNotFoundError{Bin: gitLog.config.Bin}
 	}
 
-	if err != nil {
-		return back, err
+	if _, err := os.Stat(gitLog.dir); err != nil {
+		return &NotRepositoryError{Path: gitLog.config.Path, Err: err}
 	}
 
-	dir, err := filepath.Abs(gitLog.config.Path)
-	if err != nil {
-		return back, err
+	// Check inside work tree
+	out, err := gitLog.exec("rev-parse", "--is-inside-work-tree")
+	if err == nil && out != "true" {
+		err = fmt.Errorf("\"%s\" is outside the work tree", gitLog.dir)
 	}
 
-	err = os.Chdir(dir)
 	if err != nil {
-		return back, err
+		return &NotRepositoryError{Path: gitLog.config.Path, Err: err}
+	}
+
+	return nil
+}
+
+// exec executes the git command in the repository
+// `git -C` is used instead of changing the working directory, so GitLog can be used concurrently
+func (gitLog *Client) exec(subcmd string, args ...string) (string, error) {
+	return gitLog.client.Exec("-C", append([]string{gitLog.dir, subcmd}, args...)...)
+}
+
+// execRaw is the same as exec, but the output is not trimmed and stdin is passed to the git command
+func (gitLog *Client) execRaw(stdin io.Reader, subcmd string, args ...string) (string, error) {
+	var out bytes.Buffer
+	cmd := exec.Command(gitLog.config.Bin, append([]string{"-C", gitLog.dir, subcmd}, args...)...)
+	cmd.Stdin = stdin
+	cmd.Stdout = &out
+	cmd.Stderr = ioutil.Discard
+
+	if err := cmd.Run(); err != nil {
+		return "", err
 	}
 
-	return back, nil
+	return out.String(), nil
 }
 
 // Build command line args
-func (gitLog *gitLogImpl) buildArgs(rev RevArgs, params *Params) []string {
-	args := []string{
-		"--no-decorate",
-		"--pretty=\"" + logFormat + "\"",
+// if numstat is true, `--numstat` is given and the output of each commit has the stats field at the end
+func (gitLog *Client) buildArgs(rev RevArgs, params *Params, numstat bool) []string {
+	format := logFormat
+	if params != nil && params.Mailmap {
+		format = mailmapLogFormat
 	}
 
+	args := []string{"--no-decorate"}
+
+	if numstat {
+		format += delimiter + statsField + ":"
+		args = append(args, "--numstat")
+	}
+
+	args = append(args, "--pretty=\""+format+"\"")
+
+	return append(args, gitLog.buildRevArgs(rev, params)...)
+}
+
+// Build command line args of params and revisions
+func (gitLog *Client) buildRevArgs(rev RevArgs, params *Params) []string {
+	args := []string{}
+
 	if params != nil {
 		if params.MergesOnly {
 			args = append(args, "--merges")
@@ -133,6 +228,26 @@ func (gitLog *gitLogImpl) buildArgs(rev RevArgs, params *Params) []string {
 		if params.Reverse {
 			args = append(args, "--reverse")
 		}
+
+		if params.FirstParent {
+			args = append(args, "--first-parent")
+		}
+
+		if params.AncestryPath {
+			args = append(args, "--ancestry-path")
+		}
+
+		if order := params.Order.arg(); order != "" {
+			args = append(args, order)
+		}
+
+		if params.SimplifyByDecoration {
+			args = append(args, "--simplify-by-decoration")
+		}
+
+		if params.Boundary {
+			args = append(args, "--boundary")
+		}
 	}
 
 	if rev != nil {
@@ -140,42 +255,213 @@ func (gitLog *gitLogImpl) buildArgs(rev RevArgs, params *Params) []string {
 		args = append(args, revisions...)
 	}
 
+	// Revisions are never treated as paths
+	args = append(args, "--")
+
 	return args
 }
 
 // Log internally uses the git command to get a list of git-logs
 // func (gitLog *gitLogImpl) Log(ref string, rev RevArgs) ([]*Commit, error) {
-func (gitLog *gitLogImpl) Log(rev RevArgs, params *Params) ([]*Commit, error) {
-	// Can execute the git command?
-	if err := gitLog.client.CanExec(); err != nil {
-		return nil, err
+func (gitLog *Client) Log(rev RevArgs, params *Params) ([]*Commit, error) {
+	commits, _, err := gitLog.log(rev, params, false)
+	return commits, err
+}
+
+// Count returns the number of commits of Log without dumping them, alias for `git rev-list --count`
+func (gitLog *Client) Count(rev RevArgs, params *Params) (int, error) {
+	rev, err := gitLog.checkRev(rev, params)
+	if err != nil {
+		return 0, err
 	}
 
-	// To repository path
-	back, err := gitLog.workdir()
+	args := gitLog.buildRevArgs(rev, params)
+
+	// rev-list does not start from HEAD by default unlike git-log
+	if startsFromHead(rev) {
+		args = append(args[:len(args)-1], "HEAD", "--")
+	}
+
+	out, err := gitLog.exec("rev-list", append([]string{"--count"}, args...)...)
 	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(out)
+}
+
+// checkRev checks rev and params before running git, rev is resolved if params.Verify is true
+func (gitLog *Client) checkRev(rev RevArgs, params *Params) (RevArgs, error) {
+	// Reject incompatible options before running anything
+	if err := params.validate(); err != nil {
 		return nil, err
 	}
-	defer back()
 
-	// Check inside work tree
-	err = gitLog.client.InsideWorkTree()
-	if err != nil {
+	if validator, ok := rev.(RevValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := gitLog.prepare(); err != nil {
 		return nil, err
 	}
 
+	// Resolve refs before git-log
+	if params != nil && params.Verify {
+		resolved, err := gitLog.resolve(rev)
+		if err != nil {
+			return nil, err
+		}
+		return resolved, nil
+	}
+
+	return rev, nil
+}
+
+// log dumps git-log, the lines added and removed by each commit are also returned if numstat is true
+// both are taken from the same run, so they are consistent even if the refs are updated
+func (gitLog *Client) log(rev RevArgs, params *Params, numstat bool) ([]*Commit, map[string][2]int, error) {
+	rev, err := gitLog.checkRev(rev, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Dump git-log
-	args := gitLog.buildArgs(rev, params)
+	args := gitLog.buildArgs(rev, params, numstat)
 
-	out, err := gitLog.client.Exec("log", args...)
+	out, err := gitLog.exec("log", args...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	commits, err := gitLog.parser.parse(&out)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// `%m` is `>` for every commit without `--left-right`, so the side is kept only for the symmetric difference
+	if sides, ok := rev.(revSides); !ok || len(sides.sideArgs()) == 0 {
+		clearSides(commits)
+	} else if !sidesMarked(commits) {
+		// Mark the side of symmetric difference, only when `%m` is taken by `--cherry-mark`
+		err = gitLog.markSides(commits, sides.sideArgs())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if !numstat {
+		return commits, nil, nil
+	}
+
+	stats := map[string][2]int{}
+	marker := delimiter + statsField + ":"
+
+	for i, record := range strings.Split(out, separator)[1:] {
+		if j := strings.Index(record, marker); j >= 0 && commits[i].Hash != nil {
+			stats[commits[i].Hash.Long] = parseNumstat(record[j+len(marker):])
+		}
+	}
+
+	return commits, stats, nil
+}
+
+// SinceRef returns RevTime since the committer date of ref
+// if ref is empty, the most recent tag reachable from HEAD is used, the tags pointing at HEAD are skipped
+func (gitLog *Client) SinceRef(ref string) (*RevTime, error) {
+	if err := gitLog.prepare(); err != nil {
+		return nil, err
+	}
+
+	if ref == "" {
+		var err error
+		ref, err = gitLog.previousTag()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ValidateRevision(ref); err != nil {
+		return nil, err
+	}
+
+	out, err := gitLog.exec("log", "-1", "--format=%ct", ref, "--")
 	if err != nil {
 		return nil, err
 	}
 
-	return commits, nil
+	timestamp, err := strconv.ParseInt(out, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RevTime{
+		Since: time.Unix(timestamp, 0),
+	}, nil
+}
+
+// previousTag returns the most recent tag reachable from HEAD except the tags of HEAD itself
+func (gitLog *Client) previousTag() (string, error) {
+	out, err := gitLog.exec("tag", "--points-at", "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"--tags", "--abbrev=0"}
+	for _, tag := range splitLines(out) {
+		args = append(args, "--exclude="+tag)
+	}
+
+	return gitLog.exec("describe", append(args, "HEAD")...)
+}
+
+// clearSides resets Commit.Side taken from `%m` without `--left-right`
+func clearSides(commits []*Commit) {
+	for _, commit := range commits {
+		commit.Side = SideNone
+	}
+}
+
+// sidesMarked returns true if all commits except boundaries have Side parsed from `%m`
+func sidesMarked(commits []*Commit) bool {
+	for _, commit := range commits {
+		if commit.Side == SideNone && !commit.Boundary {
+			return false
+		}
+	}
+	return true
+}
+
+// markSides fills Commit.Side using the output of `rev-list --left-right`
+func (gitLog *Client) markSides(commits []*Commit, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	out, err := gitLog.exec("rev-list", append(args, "--")...)
+	if err != nil {
+		return err
+	}
+
+	sides := map[string]Side{}
+
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) < 2 {
+			continue
+		}
+
+		switch line[0] {
+		case '<':
+			sides[line[1:]] = SideLeft
+		case '>':
+			sides[line[1:]] = SideRight
+		}
+	}
+
+	for _, commit := range commits {
+		commit.Side = sides[commit.Hash.Long]
+	}
+
+	return nil
 }
@@ -1,11 +1,13 @@
 package gitlog
 
 import (
+	"io/ioutil"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -145,6 +147,7 @@ func TestGitLog(t *testing.T) {
 		assert.Equal(expect[0], commit.Subject)
 		assert.Equal(expect[1], commit.Body)
 		assert.Equal(expect[2], commit.Tag.Name)
+		assert.Equal(SideNone, commit.Side)
 	}
 }
 
@@ -173,6 +176,152 @@ func TestGitLogNumber(t *testing.T) {
 	}
 }
 
+func TestGitLogGroup(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	git := New(&Config{
+		Path: ".tmp",
+	})
+
+	commits, err := git.Log(&RevGroup{
+		Revs: []RevArgs{
+			&RevRange{Old: "v1.0.0", New: "3.6.4-beta.12"},
+			&RevNumber{2},
+			&RevSkip{1},
+		},
+	}, nil)
+
+	assert.Nil(err)
+	assert.Equal(2, len(commits))
+	assert.Equal("style(*): Run GoFmt", commits[0].Subject)
+	assert.Equal("fix(logger): Fix bar function", commits[1].Subject)
+
+	commits, err = git.Log(&RevGroup{
+		Revs: []RevArgs{
+			&RevAll{},
+			&Rev{"master"},
+		},
+	}, nil)
+
+	assert.Nil(commits)
+	assert.IsType(&RevConflictError{}, err)
+}
+
+func TestGitLogSymmetric(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	commitFile := func(name, msg string) {
+		ioutil.WriteFile(filepath.Join(".tmp", name), []byte(name), 0644)
+		git("-C", ".tmp", "add", name)
+		git("-C", ".tmp", "commit", "-m", msg)
+	}
+
+	git("-C", ".tmp", "checkout", "-b", "release", "v1.0.0")
+	commitFile("a", "fix(a): Backported fix")
+	commitFile("c", "fix(c): Release only fix")
+	git("-C", ".tmp", "checkout", "master")
+	commitFile("b", "fix(b): Master only fix")
+	git("-C", ".tmp", "cherry-pick", "release~1")
+
+	gitLog := New(&Config{
+		Path: ".tmp",
+	})
+
+	commits, err := gitLog.Log(&RevSymmetric{
+		Left:       "master",
+		Right:      "release",
+		CherryMark: true,
+	}, nil)
+
+	assert.Nil(err)
+
+	marks := map[string][]interface{}{}
+	for _, commit := range commits {
+		marks[commit.Subject] = append(marks[commit.Subject], commit.Side, commit.Equivalent)
+	}
+
+	assert.Equal([]interface{}{SideLeft, true, SideRight, true}, marks["fix(a): Backported fix"])
+	assert.Equal([]interface{}{SideRight, false}, marks["fix(c): Release only fix"])
+	assert.Equal([]interface{}{SideLeft, false}, marks["fix(b): Master only fix"])
+	assert.Equal(10, len(commits))
+
+	commits, err = gitLog.Log(&RevSymmetric{
+		Left:       "master",
+		Right:      "release",
+		CherryPick: true,
+	}, nil)
+
+	assert.Nil(err)
+	assert.Equal(8, len(commits))
+
+	for _, commit := range commits {
+		assert.NotEqual(SideNone, commit.Side)
+		assert.False(commit.Equivalent)
+	}
+}
+
+func TestGitLogSet(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	git("-C", ".tmp", "branch", "release/1.x", "v1.0.0")
+	git("-C", ".tmp", "branch", "release/2.x", "2.1.0")
+	git("-C", ".tmp", "branch", "release/3.x", "v3.0.0-rc.10")
+
+	gitLog := New(&Config{
+		Path: ".tmp",
+	})
+
+	commits, err := gitLog.Log(&RevSet{
+		Branches: []string{"release/*"},
+		Ignore:   []string{"release/3.x"},
+		Exclude:  []string{"v1.0.0"},
+	}, nil)
+
+	assert.Nil(err)
+	assert.Equal(3, len(commits))
+	assert.Equal("Merge pull request #12 from tsuyoshiwada/topic", commits[0].Subject)
+
+	// scoped excludes and `--not`
+	commits, err = gitLog.Log(&RevSet{
+		Globs: []*RefGlob{{Type: RefTypeBranch, Pattern: "release/*", Exclude: []string{"release/3.x"}}},
+		Not:   []*RefGlob{{Type: RefTypeTag, Pattern: "v1.*"}},
+	}, nil)
+
+	assert.Nil(err)
+	assert.Equal(3, len(commits))
+	assert.Equal("Merge pull request #12 from tsuyoshiwada/topic", commits[0].Subject)
+
+	commits, err = gitLog.Log(&RevSet{
+		Globs: []*RefGlob{{Type: RefTypeTag, Exclude: []string{"3.*", "v3.*"}}},
+	}, nil)
+
+	assert.Nil(err)
+	assert.Equal(4, len(commits))
+	assert.Equal("Merge pull request #12 from tsuyoshiwada/topic", commits[0].Subject)
+
+	_, err = gitLog.Log(&RevSet{Globs: []*RefGlob{{Pattern: "x"}}}, nil)
+	assert.IsType(&RevConflictError{}, err)
+
+	commits, err = gitLog.Log(&RevSet{
+		Include: []string{"v3.0.0-rc.10", "topic"},
+		Exclude: []string{"2.1.0"},
+	}, nil)
+
+	assert.Nil(err)
+	assert.Equal(2, len(commits))
+	assert.Equal("style(*): Run GoFmt", commits[0].Subject)
+	assert.Equal("fix(logger): Fix bar function", commits[1].Subject)
+}
+
 func TestGitLogMergesOnly(t *testing.T) {
 	assert := assert.New(t)
 
@@ -238,7 +387,246 @@ func TestGitLogReverse(t *testing.T) {
 	}
 }
 
-// FIXME: Time tests
+func TestGitLogCount(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	count, err := gitLog.Count(nil, nil)
+	assert.Nil(err)
+	assert.Equal(7, count)
+
+	count, err = gitLog.Count(&RevRange{Old: "v1.0.0", New: "v3.0.0-rc.10"}, &Params{IgnoreMerges: true})
+	assert.Nil(err)
+	assert.Equal(4, count)
+
+	count, err = gitLog.Count(&RevGroup{Revs: []RevArgs{&RevSkip{5}, &RevNumber{10}}}, &Params{Reverse: true})
+	assert.Nil(err)
+	assert.Equal(2, count)
+
+	_, err = gitLog.Count(&Rev{Ref: "notfound"}, &Params{Verify: true})
+	assert.Equal(&RefError{Missing: []string{"notfound"}}, err)
+}
+
+func TestGitLogFirstParent(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	git := New(&Config{
+		Path: ".tmp",
+	})
+
+	commits, err := git.Log(nil, &Params{
+		FirstParent: true,
+	})
+
+	assert.Nil(err)
+	assert.Equal(6, len(commits))
+
+	for _, commit := range commits {
+		assert.NotEqual("docs(readme): Has body commit message", commit.Subject)
+	}
+}
+
+func TestGitLogAncestryPath(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	git := New(&Config{
+		Path: ".tmp",
+	})
+
+	commits, err := git.Log(&RevRange{
+		Old: "v1.0.0",
+		New: "2.1.0",
+	}, &Params{
+		AncestryPath: true,
+		Order:        OrderTopo,
+	})
+
+	assert.Nil(err)
+	assert.Equal(3, len(commits))
+	assert.Equal("Merge pull request #12 from tsuyoshiwada/topic", commits[0].Subject)
+}
+
+func TestGitLogSimplifyByDecoration(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	git := New(&Config{
+		Path: ".tmp",
+	})
+
+	commits, err := git.Log(nil, &Params{
+		SimplifyByDecoration: true,
+	})
+
+	assert.Nil(err)
+	assert.Equal(4, len(commits))
+
+	table := []string{
+		"chore(release): Bump version to v0.0.0",
+		"style(*): Run GoFmt",
+		"Merge pull request #12 from tsuyoshiwada/topic",
+		"docs(readme): Has body commit message",
+	}
+
+	for i, commit := range commits {
+		assert.Equal(table[i], commit.Subject)
+	}
+}
+
+func TestGitLogBoundary(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	git := New(&Config{
+		Path: ".tmp",
+	})
+
+	commits, err := git.Log(&RevRange{
+		Old: "2.1.0",
+		New: "v3.0.0-rc.10",
+	}, &Params{
+		Boundary: true,
+	})
+
+	assert.Nil(err)
+	assert.Equal(3, len(commits))
+
+	table := []bool{false, false, true}
+
+	for i, commit := range commits {
+		assert.Equal(table[i], commit.Boundary)
+	}
+	assert.Equal("Merge pull request #12 from tsuyoshiwada/topic", commits[2].Subject)
+}
+
+func TestGitLogIncompatibleParams(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	git := New(&Config{
+		Path: ".tmp",
+	})
+
+	commits, err := git.Log(nil, &Params{
+		MergesOnly:   true,
+		IgnoreMerges: true,
+	})
+
+	assert.Nil(commits)
+	assert.IsType(&ParamsError{}, err)
+}
+
+func TestGitLogTime(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	commitAt := func(date, msg string) {
+		os.Setenv("GIT_COMMITTER_DATE", date)
+		os.Setenv("GIT_AUTHOR_DATE", date)
+		git("-C", ".tmp", "commit", "--allow-empty", "-m", msg)
+		os.Unsetenv("GIT_COMMITTER_DATE")
+		os.Unsetenv("GIT_AUTHOR_DATE")
+	}
+
+	git("-C", ".tmp", "checkout", "-b", "history", "v1.0.0")
+	commitAt("2018-01-01T23:59:59Z", "before")
+	commitAt("2018-01-02T00:00:00Z", "first")
+	commitAt("2018-01-02T12:00:00Z", "last")
+	commitAt("2018-01-02T12:00:01Z", "after")
+
+	// git reads dates without offset in its local timezone
+	tz, hasTZ := os.LookupEnv("TZ")
+	os.Setenv("TZ", "Pacific/Honolulu")
+	defer func() {
+		if hasTZ {
+			os.Setenv("TZ", tz)
+		} else {
+			os.Unsetenv("TZ")
+		}
+	}()
+
+	gitLog := New(&Config{
+		Path: ".tmp",
+	})
+
+	commits, err := gitLog.Log(&RevTime{
+		Since: time.Date(2018, 1, 2, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+		Until: time.Date(2018, 1, 2, 7, 0, 0, 0, time.FixedZone("EST", -5*60*60)),
+	}, nil)
+
+	assert.Nil(err)
+	assert.Equal(2, len(commits))
+	assert.Equal("last", commits[0].Subject)
+	assert.Equal("first", commits[1].Subject)
+}
+
+func TestGitLogSinceRef(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	git("-C", ".tmp", "checkout", "-b", "unreleased")
+	git("-C", ".tmp", "commit", "--allow-empty", "--date=2018-01-02T00:00:00Z", "-m", "unreleased")
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	tagged, _ := gitLog.Log(&Rev{"3.6.4-beta.12"}, &Params{})
+
+	rev, err := gitLog.SinceRef("")
+	assert.Nil(err)
+	assert.Equal(tagged[0].Committer.Date, rev.Since)
+	assert.True(rev.Until.IsZero())
+
+	tagged, _ = gitLog.Log(&Rev{"v1.0.0"}, nil)
+
+	rev, err = gitLog.SinceRef("v1.0.0")
+	assert.Nil(err)
+	assert.Equal(tagged[0].Committer.Date, rev.Since)
+
+	rev, err = gitLog.SinceRef("notfound")
+	assert.Nil(rev)
+	assert.NotNil(err)
+
+	tag, err := gitLog.previousTag()
+	assert.Nil(err)
+	assert.Equal("3.6.4-beta.12", tag)
+
+	// the tag of HEAD is skipped
+	git("-C", ".tmp", "checkout", "master")
+
+	tag, err = gitLog.previousTag()
+	assert.Nil(err)
+	assert.Equal("v3.0.0-rc.10", tag)
+
+	git("-C", ".tmp", "tag", "v4.0.0")
+
+	tag, err = gitLog.previousTag()
+	assert.Nil(err)
+	assert.Equal("v3.0.0-rc.10", tag)
+}
 
 func TestGitLogNotFoundGitCommand(t *testing.T) {
 	assert := assert.New(t)
@@ -255,6 +643,7 @@ func TestGitLogNotFoundGitCommand(t *testing.T) {
 
 	assert.Nil(commits)
 	assert.Contains(err.Error(), "does not exists")
+	assert.Equal(&GitNotFoundError{Bin: "/notfound/git/bin"}, err)
 }
 
 func TestGitLogNotFoundPath(t *testing.T) {
@@ -272,4 +661,22 @@ func TestGitLogNotFoundPath(t *testing.T) {
 
 	assert.Nil(commits)
 	assert.Contains(err.Error(), "no such file or directory")
+	assert.IsType(&NotRepositoryError{}, err)
+}
+
+func TestGitLogNotRepository(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, _ := ioutil.TempDir("", "go-gitlog")
+	defer os.RemoveAll(dir)
+
+	git := New(&Config{
+		Path: dir,
+	})
+
+	commits, err := git.Log(nil, nil)
+
+	assert.Nil(commits)
+	assert.IsType(&NotRepositoryError{}, err)
+	assert.Equal(dir, err.(*NotRepositoryError).Path)
 }
@@ -0,0 +1,416 @@
+// Package httpapi is providing a means to serve git-log over HTTP as JSON.
+//
+// The response body is the JSON array of gitlog.EncodeJSON. The query parameters are
+//
+//	rev                     revision expression of gitlog.ParseRev (e.g. `v1.0.0..HEAD`)
+//	since, until            date of `2006-01-02`, RFC 3339 or relative expression (e.g. `2 weeks ago`)
+//	merges, no_merges, reverse, first_parent, ancestry_path, simplify_by_decoration, boundary, mailmap
+//	                        boolean of gitlog.Params
+//	order                   `date`, `author-date` or `topo`
+//	page, per_page          paging, the next page is given by `Link` header
+package httpapi
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	gitlog "github.com/tsuyoshiwada/go-gitlog"
+)
+
+// Options for Handler
+type Options struct {
+	PerPage    int         // default 30
+	MaxPerPage int         // default 100
+	ErrorLog   *log.Logger // errors of writing the response, the standard logger of log package is used if nil
+}
+
+// Git is the subset of gitlog.Client used by Handler
+type Git interface {
+	gitlog.GitLog
+	Count(gitlog.RevArgs, *gitlog.Params) (int, error)
+	Resolve(gitlog.RevArgs) (*gitlog.ResolvedRev, error)
+	Refs(*gitlog.RefsParams) ([]*gitlog.Ref, error)
+}
+
+// Handler serves git-log as JSON
+type Handler struct {
+	git     Git
+	options *Options
+}
+
+// New Handler
+func New(git Git, options *Options) *Handler {
+	opts := &Options{}
+	if options != nil {
+		*opts = *options
+	}
+
+	if opts.MaxPerPage <= 0 {
+		opts.MaxPerPage = 100
+	}
+
+	if opts.PerPage <= 0 || opts.PerPage > opts.MaxPerPage {
+		opts.PerPage = 30
+		if opts.PerPage > opts.MaxPerPage {
+			opts.PerPage = opts.MaxPerPage
+		}
+	}
+
+	return &Handler{
+		git:     git,
+		options: opts,
+	}
+}
+
+// QueryError is returned when the query parameter is invalid
+type QueryError struct {
+	Param  string
+	Reason string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("\"%s\" %s", e.Param, e.Reason)
+}
+
+// errorResponse is the body of error
+type errorResponse struct {
+	Error *errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Type    string      `json:"type"`
+	Message string      `json:"message"`
+	Detail  interface{} `json:"detail,omitempty"` // fields of the error
+}
+
+var booleanParams = []struct {
+	name  string
+	field func(*gitlog.Params) *bool
+}{
+	{"merges", func(p *gitlog.Params) *bool { return &p.MergesOnly }},
+	{"no_merges", func(p *gitlog.Params) *bool { return &p.IgnoreMerges }},
+	{"reverse", func(p *gitlog.Params) *bool { return &p.Reverse }},
+	{"first_parent", func(p *gitlog.Params) *bool { return &p.FirstParent }},
+	{"ancestry_path", func(p *gitlog.Params) *bool { return &p.AncestryPath }},
+	{"simplify_by_decoration", func(p *gitlog.Params) *bool { return &p.SimplifyByDecoration }},
+	{"boundary", func(p *gitlog.Params) *bool { return &p.Boundary }},
+	{"mailmap", func(p *gitlog.Params) *bool { return &p.Mailmap }},
+}
+
+var orders = map[string]gitlog.Order{
+	"":            gitlog.OrderDefault,
+	"date":        gitlog.OrderDate,
+	"author-date": gitlog.OrderAuthorDate,
+	"topo":        gitlog.OrderTopo,
+}
+
+// request is the parsed query
+type request struct {
+	rev     gitlog.RevArgs
+	params  *gitlog.Params
+	page    int
+	perPage int
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		h.writeError(w, http.StatusMethodNotAllowed, &QueryError{Param: "method", Reason: "is not allowed"})
+		return
+	}
+
+	req, err := h.parse(r.URL.Query())
+	if err != nil {
+		h.writeError(w, statusOf(err), err)
+		return
+	}
+
+	// Pin refs, so the ETag and the commits are of the same snapshot
+	resolved, err := h.git.Resolve(req.rev)
+	if err != nil {
+		h.writeError(w, statusOf(err), err)
+		return
+	}
+
+	etag, err := h.etag(req, resolved)
+	if err != nil {
+		h.writeError(w, statusOf(err), err)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+
+	if matchETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	commits, err := h.page(resolved, req)
+	if err != nil {
+		h.writeError(w, statusOf(err), err)
+		return
+	}
+
+	if len(commits) > req.perPage {
+		commits = commits[:req.perPage]
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", pageURL(r.URL, req.page+1, req.perPage)))
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	if err := gitlog.EncodeJSON(w, commits); err != nil {
+		h.logf("httpapi: failed to write commits: %v", err)
+	}
+}
+
+// page returns the commits of the page and one more commit to know whether the next page exists
+func (h *Handler) page(rev gitlog.RevArgs, req *request) ([]*gitlog.Commit, error) {
+	offset := (req.page - 1) * req.perPage
+	limit := req.perPage + 1
+
+	// git applies `--skip` and `-n` before `--reverse`, so the page is counted from the oldest
+	if req.params.Reverse {
+		total, err := h.git.Count(rev, req.params)
+		if err != nil {
+			return nil, err
+		}
+
+		offset = total - offset - limit
+		if offset < 0 {
+			limit += offset
+			offset = 0
+		}
+
+		if limit <= 0 {
+			return []*gitlog.Commit{}, nil
+		}
+	}
+
+	return h.git.Log(&gitlog.RevGroup{Revs: []gitlog.RevArgs{
+		rev,
+		&gitlog.RevSkip{Offset: offset},
+		&gitlog.RevNumber{Limit: limit},
+	}}, req.params)
+}
+
+func (h *Handler) parse(query url.Values) (*request, error) {
+	req := &request{
+		params:  &gitlog.Params{},
+		page:    1,
+		perPage: h.options.PerPage,
+	}
+
+	revs := []gitlog.RevArgs{}
+
+	if expr := query.Get("rev"); expr != "" {
+		rev, err := gitlog.ParseRev(expr)
+		if err != nil {
+			return nil, err
+		}
+		revs = append(revs, rev)
+	}
+
+	since, err := parseTime(query, "since")
+	if err != nil {
+		return nil, err
+	}
+
+	until, err := parseTime(query, "until")
+	if err != nil {
+		return nil, err
+	}
+
+	// the same instant is given to git in the same text, see etag
+	if !since.IsZero() || !until.IsZero() {
+		revs = append(revs, &gitlog.RevTime{Since: since.UTC(), Until: until.UTC()})
+	}
+
+	switch len(revs) {
+	case 1:
+		req.rev = revs[0]
+	case 2:
+		req.rev = &gitlog.RevGroup{Revs: revs}
+	}
+
+	for _, param := range booleanParams {
+		value := query.Get(param.name)
+		if value == "" {
+			continue
+		}
+
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, &QueryError{Param: param.name, Reason: "must be boolean"}
+		}
+		*param.field(req.params) = b
+	}
+
+	order, ok := orders[query.Get("order")]
+	if !ok {
+		return nil, &QueryError{Param: "order", Reason: "must be date, author-date or topo"}
+	}
+	req.params.Order = order
+
+	if req.page, err = parseInt(query, "page", 1, 0); err != nil {
+		return nil, err
+	}
+
+	if req.perPage, err = parseInt(query, "per_page", h.options.PerPage, h.options.MaxPerPage); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// parseInt parses the positive integer, max is unlimited if 0
+func parseInt(query url.Values, name string, def, max int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || (max > 0 && n > max) {
+		reason := "must be positive integer"
+		if max > 0 {
+			reason = fmt.Sprintf("must be between 1 and %d", max)
+		}
+		return 0, &QueryError{Param: name, Reason: reason}
+	}
+
+	return n, nil
+}
+
+// parseTime parses date, RFC 3339 or relative expression
+func parseTime(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if rev, err := gitlog.ParseRevTime(value); err == nil {
+		return rev.Since, nil
+	}
+
+	return time.Time{}, &QueryError{Param: name, Reason: "must be date, RFC 3339 or relative time"}
+}
+
+// etag is keyed on the resolved refs and HEAD, the refs of the repository, the arguments of git and the page
+// refs are included since patterns such as `--branches` are not resolved,
+// and the resolved dates are used instead of the query, so relative dates such as `2 weeks ago` move ETag
+func (h *Handler) etag(req *request, resolved *gitlog.ResolvedRev) (string, error) {
+	head, err := h.git.Resolve(&gitlog.Rev{Ref: "HEAD"})
+	if err != nil {
+		return "", err
+	}
+
+	refs, err := h.git.Refs(nil)
+	if err != nil {
+		return "", err
+	}
+
+	lines := []string{
+		"HEAD " + head.Refs["HEAD"],
+		fmt.Sprintf("args %q", resolved.Args()),
+		fmt.Sprintf("params %+v", *req.params),
+		fmt.Sprintf("page %d %d", req.page, req.perPage),
+	}
+
+	for ref, id := range resolved.Refs {
+		lines = append(lines, "rev "+ref+" "+id)
+	}
+
+	for _, ref := range refs {
+		lines = append(lines, "ref "+ref.Ref+" "+ref.Commit.Long)
+	}
+
+	sort.Strings(lines)
+
+	sum := sha1.Sum([]byte(strings.Join(lines, "\n")))
+
+	return "\"" + hex.EncodeToString(sum[:]) + "\"", nil
+}
+
+func matchETag(header, etag string) bool {
+	for _, value := range strings.Split(header, ",") {
+		value = strings.TrimSpace(value)
+		if value == "*" || strings.TrimPrefix(value, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func pageURL(u *url.URL, page, perPage int) string {
+	query := u.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+
+	next := *u
+	next.RawQuery = query.Encode()
+
+	return next.RequestURI()
+}
+
+// statusOf returns HTTP status of the error
+func statusOf(err error) int {
+	switch err.(type) {
+	case *QueryError, *gitlog.ParamsError, *gitlog.RevParseError, *gitlog.InvalidRevError, *gitlog.RevConflictError:
+		return http.StatusBadRequest
+	case *gitlog.RefError:
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, status int, err error) {
+	body := &errorBody{
+		Type:    reflect.Indirect(reflect.ValueOf(err)).Type().Name(),
+		Message: err.Error(),
+	}
+
+	// the fields of the server side errors such as the path are not exposed
+	if status < http.StatusInternalServerError {
+		body.Detail = err
+	} else {
+		body.Message = http.StatusText(status)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(&errorResponse{Error: body}); err != nil {
+		h.logf("httpapi: failed to write error: %v", err)
+	}
+}
+
+// logf logs the error which can not be sent to the client
+func (h *Handler) logf(format string, args ...interface{}) {
+	if h.options.ErrorLog != nil {
+		h.options.ErrorLog.Printf(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
@@ -0,0 +1,298 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	gitlog "github.com/tsuyoshiwada/go-gitlog"
+)
+
+func git(args ...string) string {
+	cmd := exec.Command("git", append([]string{"-C", ".tmp"}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_DATE=2018-01-31T12:00:00Z",
+		"GIT_COMMITTER_DATE=2018-01-31T12:00:00Z",
+	)
+	bytes, _ := cmd.Output()
+	return string(bytes)
+}
+
+func setup() func() {
+	dir, _ := filepath.Abs(".tmp")
+
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		log.Fatalln(err)
+	}
+
+	git("init")
+	git("config", "--local", "user.name", "authorname")
+	git("config", "--local", "user.email", "mail@example.com")
+
+	git("commit", "--allow-empty", "-m", "chore(*): Initial Commit")
+	git("tag", "v1.0.0")
+
+	for i := 1; i <= 4; i++ {
+		git("commit", "--allow-empty", "-m", fmt.Sprintf("feat(parser): Add feature %d", i))
+	}
+
+	return func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
+
+func get(handler http.Handler, target string, header http.Header) (*httptest.ResponseRecorder, []*gitlog.Commit) {
+	req := httptest.NewRequest("GET", target, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	commits, _ := gitlog.DecodeJSON(bytes.NewReader(rec.Body.Bytes()))
+	return rec, commits
+}
+
+// brokenWriter fails to write the body
+type brokenWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (w *brokenWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func subjects(commits []*gitlog.Commit) []string {
+	subjects := []string{}
+	for _, commit := range commits {
+		subjects = append(subjects, commit.Subject)
+	}
+	return subjects
+}
+
+func TestHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	handler := New(gitlog.NewClient(&gitlog.Config{Path: ".tmp"}), &Options{PerPage: 2})
+
+	rec, commits := get(handler, "/commits", nil)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal([]string{"feat(parser): Add feature 4", "feat(parser): Add feature 3"}, subjects(commits))
+	assert.Equal(`</commits?page=2&per_page=2>; rel="next"`, rec.Header().Get("Link"))
+
+	rec, commits = get(handler, "/commits?page=3", nil)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal([]string{"chore(*): Initial Commit"}, subjects(commits))
+	assert.Equal("", rec.Header().Get("Link"))
+
+	rec, commits = get(handler, "/commits?rev=v1.0.0..HEAD&reverse=true&per_page=10", nil)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal(4, len(commits))
+	assert.Equal("feat(parser): Add feature 1", commits[0].Subject)
+
+	// pages from the oldest
+	pages := [][]string{
+		{"chore(*): Initial Commit", "feat(parser): Add feature 1"},
+		{"feat(parser): Add feature 2", "feat(parser): Add feature 3"},
+		{"feat(parser): Add feature 4"},
+	}
+	link := "/commits?reverse=true"
+	for i, page := range pages {
+		rec, commits = get(handler, link, nil)
+		assert.Equal(http.StatusOK, rec.Code)
+		assert.Equal(page, subjects(commits), link)
+
+		link = strings.TrimSuffix(strings.TrimPrefix(rec.Header().Get("Link"), "<"), ">; rel=\"next\"")
+		if i == len(pages)-1 {
+			assert.Equal("", link)
+		}
+	}
+
+	rec, commits = get(handler, "/commits?reverse=true&page=4", nil)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal(0, len(commits))
+
+	rec, commits = get(handler, "/commits?since=2018-02-01", nil)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal(0, len(commits))
+
+	rec, commits = get(handler, "/commits?until=2018-02-01T00:00:00Z&per_page=1&order=topo", nil)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal(1, len(commits))
+}
+
+func TestHandlerETag(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	handler := New(gitlog.NewClient(&gitlog.Config{Path: ".tmp"}), nil)
+
+	rec, _ := get(handler, "/commits", nil)
+	etag := rec.Header().Get("ETag")
+	assert.NotEqual("", etag)
+
+	rec, _ = get(handler, "/commits", http.Header{"If-None-Match": {etag}})
+	assert.Equal(http.StatusNotModified, rec.Code)
+	assert.Equal(etag, rec.Header().Get("ETag"))
+	assert.Equal(0, rec.Body.Len())
+
+	rec, _ = get(handler, "/commits", http.Header{"If-None-Match": {`"other", W/` + etag}})
+	assert.Equal(http.StatusNotModified, rec.Code)
+
+	// other query
+	rec, _ = get(handler, "/commits?page=2", http.Header{"If-None-Match": {etag}})
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.NotEqual(etag, rec.Header().Get("ETag"))
+
+	// same query in the other text
+	rec, _ = get(handler, "/commits?page=1&per_page=30&merges=false", http.Header{"If-None-Match": {etag}})
+	assert.Equal(http.StatusNotModified, rec.Code)
+
+	rec, _ = get(handler, "/commits?until=2018-01-28T00:00:00Z", nil)
+	until := rec.Header().Get("ETag")
+
+	rec, _ = get(handler, "/commits?until=2018-01-28T09:00:00%2B09:00", http.Header{"If-None-Match": {until}})
+	assert.Equal(http.StatusNotModified, rec.Code)
+
+	// HEAD is moved
+	git("commit", "--allow-empty", "-m", "fix(parser): Fix feature")
+
+	rec, commits := get(handler, "/commits", http.Header{"If-None-Match": {etag}})
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.NotEqual(etag, rec.Header().Get("ETag"))
+	assert.Equal("fix(parser): Fix feature", commits[0].Subject)
+}
+
+func TestHandlerErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	handler := New(gitlog.NewClient(&gitlog.Config{Path: ".tmp"}), nil)
+
+	table := []struct {
+		target string
+		status int
+		typ    string
+	}{
+		{"/commits?rev=notfound", http.StatusNotFound, "RefError"},
+		{"/commits?rev=v1.0.0..%20^", http.StatusBadRequest, "RevParseError"},
+		{"/commits?rev=--output=/tmp/x", http.StatusBadRequest, "RevParseError"},
+		{"/commits?merges=true&no_merges=true", http.StatusBadRequest, "ParamsError"},
+		{"/commits?reverse=maybe", http.StatusBadRequest, "QueryError"},
+		{"/commits?order=random", http.StatusBadRequest, "QueryError"},
+		{"/commits?page=0", http.StatusBadRequest, "QueryError"},
+		{"/commits?per_page=101", http.StatusBadRequest, "QueryError"},
+		{"/commits?since=someday", http.StatusBadRequest, "QueryError"},
+	}
+
+	for _, test := range table {
+		rec, _ := get(handler, test.target, nil)
+		assert.Equal(test.status, rec.Code, test.target)
+
+		body := map[string]map[string]interface{}{}
+		assert.Nil(json.Unmarshal(rec.Body.Bytes(), &body), test.target)
+		assert.Equal(test.typ, body["error"]["type"], test.target)
+		assert.NotEqual("", body["error"]["message"], test.target)
+	}
+
+	rec, _ := get(handler, "/commits?rev=notfound", nil)
+	assert.JSONEq(`{"error": {
+		"type": "RefError",
+		"message": "unknown revision \"notfound\"",
+		"detail": {"Missing": ["notfound"], "Ambiguous": null}
+	}}`, rec.Body.String())
+
+	// server side errors do not expose the details
+	dir, _ := ioutil.TempDir("", "httpapi")
+	defer os.RemoveAll(dir)
+
+	rec, _ = get(New(gitlog.NewClient(&gitlog.Config{Path: dir}), nil), "/commits", nil)
+	assert.Equal(http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(`{"error": {"type": "NotRepositoryError", "message": "Internal Server Error"}}`, rec.Body.String())
+
+	req := httptest.NewRequest("POST", "/commits", nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(http.StatusMethodNotAllowed, res.Code)
+	assert.Equal("GET, HEAD", res.Header().Get("Allow"))
+}
+
+func TestHandlerConcurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	server := httptest.NewServer(New(gitlog.NewClient(&gitlog.Config{Path: ".tmp"}), nil))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			res, err := http.Get(server.URL + "/?rev=" + []string{"HEAD", "v1.0.0"}[i%2])
+			if err != nil {
+				return
+			}
+			defer res.Body.Close()
+
+			commits, _ := gitlog.DecodeJSON(res.Body)
+			results[i] = len(commits)
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i, n := range results {
+		assert.Equal([]int{5, 1}[i%2], n)
+	}
+
+	res, err := http.Head(server.URL)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.True(strings.HasPrefix(res.Header.Get("ETag"), "\""))
+}
+
+func TestHandlerWriteError(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	var buf bytes.Buffer
+	handler := New(gitlog.NewClient(&gitlog.Config{Path: ".tmp"}), &Options{ErrorLog: log.New(&buf, "", 0)})
+
+	handler.ServeHTTP(&brokenWriter{httptest.NewRecorder()}, httptest.NewRequest("GET", "/commits", nil))
+	assert.Equal("httpapi: failed to write commits: broken pipe\n", buf.String())
+
+	buf.Reset()
+	handler.ServeHTTP(&brokenWriter{httptest.NewRecorder()}, httptest.NewRequest("GET", "/commits?page=0", nil))
+	assert.Equal("httpapi: failed to write error: broken pipe\n", buf.String())
+}
@@ -0,0 +1,164 @@
+package gitlog
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// Mailmap maps the identities of commits to the canonical ones like `.mailmap` of git
+type Mailmap struct {
+	entries map[string]*mailmapEntry // keyed by lower case commit email
+}
+
+type mailmapIdentity struct {
+	name  string
+	email string
+}
+
+type mailmapEntry struct {
+	mailmapIdentity                             // matches the email only
+	names           map[string]*mailmapIdentity // keyed by lower case commit name
+}
+
+// ParseMailmap parses the mailmap, the following forms are supported
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+func ParseMailmap(r io.Reader) (*Mailmap, error) {
+	mailmap := &Mailmap{
+		entries: map[string]*mailmapEntry{},
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		mailmap.parseLine(scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return mailmap, nil
+}
+
+// ReadMailmap reads the mailmap file
+func ReadMailmap(filename string) (*Mailmap, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseMailmap(file)
+}
+
+// parseLine adds the entry of line, malformed lines are ignored as git does
+func (mailmap *Mailmap) parseLine(line string) {
+	if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return
+	}
+
+	names := []string{}
+	emails := []string{}
+
+	for len(emails) < 2 {
+		begin := strings.Index(line, "<")
+		if begin < 0 {
+			break
+		}
+
+		end := strings.Index(line[begin:], ">")
+		if end < 0 {
+			break
+		}
+
+		names = append(names, strings.TrimSpace(line[:begin]))
+		emails = append(emails, strings.TrimSpace(line[begin+1:begin+end]))
+		line = line[begin+end+1:]
+	}
+
+	switch len(emails) {
+	case 1:
+		if names[0] != "" {
+			mailmap.add(names[0], "", "", emails[0])
+		}
+	case 2:
+		mailmap.add(names[0], emails[0], names[1], emails[1])
+	}
+}
+
+func (mailmap *Mailmap) add(properName, properEmail, commitName, commitEmail string) {
+	key := strings.ToLower(commitEmail)
+
+	entry, ok := mailmap.entries[key]
+	if !ok {
+		entry = &mailmapEntry{
+			names: map[string]*mailmapIdentity{},
+		}
+		mailmap.entries[key] = entry
+	}
+
+	identity := &entry.mailmapIdentity
+	if commitName != "" {
+		identity, ok = entry.names[strings.ToLower(commitName)]
+		if !ok {
+			identity = &mailmapIdentity{}
+			entry.names[strings.ToLower(commitName)] = identity
+		}
+	}
+
+	if properName != "" {
+		identity.name = properName
+	}
+
+	if properEmail != "" {
+		identity.email = properEmail
+	}
+}
+
+// Map returns the canonical name and email
+func (mailmap *Mailmap) Map(name, email string) (string, string) {
+	entry, ok := mailmap.entries[strings.ToLower(email)]
+	if !ok {
+		return name, email
+	}
+
+	identity := &entry.mailmapIdentity
+	if named, ok := entry.names[strings.ToLower(name)]; ok {
+		identity = named
+	}
+
+	if identity.name != "" {
+		name = identity.name
+	}
+
+	if identity.email != "" {
+		email = identity.email
+	}
+
+	return name, email
+}
+
+// Apply maps Author and Committer of commits, the identities before mapping are kept in RawName and RawEmail
+// commits that are already mapped are mapped again from RawName and RawEmail
+func (mailmap *Mailmap) Apply(commits []*Commit) {
+	for _, commit := range commits {
+		if author := commit.Author; author != nil {
+			if author.RawName == "" && author.RawEmail == "" {
+				author.RawName, author.RawEmail = author.Name, author.Email
+			}
+			author.Name, author.Email = mailmap.Map(author.RawName, author.RawEmail)
+		}
+
+		if committer := commit.Committer; committer != nil {
+			if committer.RawName == "" && committer.RawEmail == "" {
+				committer.RawName, committer.RawEmail = committer.Name, committer.Email
+			}
+			committer.Name, committer.Email = mailmap.Map(committer.RawName, committer.RawEmail)
+		}
+	}
+}
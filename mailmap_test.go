@@ -0,0 +1,104 @@
+package gitlog
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testMailmap = `# comment
+Proper Name <proper@example.com>
+<canonical@example.com> <old@example.com>
+Alice <alice@example.com> <ALICE@old.example.com>
+Bob <bob@example.com> bob <shared@example.com>
+Robert <robert@example.com> Robert <shared@example.com>
+malformed <line
+`
+
+func TestMailmap(t *testing.T) {
+	assert := assert.New(t)
+
+	mailmap, err := ParseMailmap(strings.NewReader(testMailmap))
+	assert.Nil(err)
+
+	table := []struct {
+		name        string
+		email       string
+		expectName  string
+		expectEmail string
+	}{
+		{"proper", "proper@example.com", "Proper Name", "proper@example.com"},
+		{"old name", "old@example.com", "old name", "canonical@example.com"},
+		{"alice", "alice@old.example.com", "Alice", "alice@example.com"},
+		{"BOB", "shared@example.com", "Bob", "bob@example.com"},
+		{"robert", "Shared@example.com", "Robert", "robert@example.com"},
+		{"someone", "shared@example.com", "someone", "shared@example.com"},
+		{"unknown", "unknown@example.com", "unknown", "unknown@example.com"},
+	}
+
+	for _, test := range table {
+		name, email := mailmap.Map(test.name, test.email)
+		assert.Equal(test.expectName, name)
+		assert.Equal(test.expectEmail, email)
+	}
+
+	commits := []*Commit{
+		{
+			Author:    &Author{Name: "alice", Email: "alice@old.example.com"},
+			Committer: &Committer{Name: "unknown", Email: "unknown@example.com"},
+		},
+	}
+
+	mailmap.Apply(commits)
+	assert.Equal(&Author{Name: "Alice", Email: "alice@example.com", RawName: "alice", RawEmail: "alice@old.example.com"}, commits[0].Author)
+	assert.Equal(&Committer{Name: "unknown", Email: "unknown@example.com", RawName: "unknown", RawEmail: "unknown@example.com"}, commits[0].Committer)
+
+	// mapped from the raw identity again
+	other, _ := ParseMailmap(strings.NewReader("Alice Smith <alice@old.example.com>"))
+	other.Apply(commits)
+	assert.Equal("Alice Smith", commits[0].Author.Name)
+	assert.Equal("alice@old.example.com", commits[0].Author.Email)
+	assert.Equal("alice", commits[0].Author.RawName)
+
+	_, err = ReadMailmap("notfound")
+	assert.NotNil(err)
+}
+
+func TestGitLogMailmap(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	ioutil.WriteFile(filepath.Join(".tmp", ".mailmap"), []byte("Proper Name <proper@example.com> <mail@example.com>\n"), 0644)
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	commits, err := gitLog.Log(nil, nil)
+	assert.Nil(err)
+	assert.Equal("authorname", commits[0].Author.Name)
+	assert.Equal("", commits[0].Author.RawName)
+
+	commits, err = gitLog.Log(nil, &Params{Mailmap: true})
+	assert.Nil(err)
+	assert.Equal(7, len(commits))
+	assert.Equal("Proper Name", commits[0].Author.Name)
+	assert.Equal("proper@example.com", commits[0].Author.Email)
+	assert.Equal("authorname", commits[0].Author.RawName)
+	assert.Equal("mail@example.com", commits[0].Author.RawEmail)
+	assert.Equal("Proper Name", commits[0].Committer.Name)
+	assert.Equal("mail@example.com", commits[0].Committer.RawEmail)
+	assert.Equal("chore(release): Bump version to v0.0.0", commits[0].Subject)
+
+	mailmap, err := ReadMailmap(filepath.Join(".tmp", ".mailmap"))
+	assert.Nil(err)
+
+	raw, _ := gitLog.Log(nil, nil)
+	mailmap.Apply(raw)
+	assert.Equal(commits[0].Author, raw[0].Author)
+}
@@ -0,0 +1,141 @@
+package gitlog
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Repository for MultiLog
+type Repository struct {
+	Name   string  // name to identify the repository, default Config.Path
+	Config *Config // required
+}
+
+// MultiLogParams for getting git-log of repositories
+type MultiLogParams struct {
+	Concurrency int // number of repositories processed at once, default 4
+}
+
+// RepositoryCommit is the commit with its repository
+type RepositoryCommit struct {
+	*Commit
+	Repository string // Repository.Name
+}
+
+// RepositoryError is the error of a repository, it does not abort the other repositories
+type RepositoryError struct {
+	Repository string // Repository.Name
+	Err        error
+}
+
+func (e *RepositoryError) Error() string {
+	return fmt.Sprintf("\"%s\": %s", e.Repository, e.Err)
+}
+
+// MultiLogResult is the result of MultiLog
+type MultiLogResult struct {
+	Commits []*RepositoryCommit // merged by committer date, newest first (oldest first if Params.Reverse)
+	Errors  []*RepositoryError  // in the order of repositories
+}
+
+// MultiLog runs Log on each repository with the same RevArgs and Params, and merges the commits into one timeline
+// the error of MultiLog is returned only for invalid arguments, the errors of repositories are reported in MultiLogResult.Errors.
+func MultiLog(repos []*Repository, rev RevArgs, params *Params, multi *MultiLogParams) (*MultiLogResult, error) {
+	concurrency := 4
+	if multi != nil && multi.Concurrency != 0 {
+		concurrency = multi.Concurrency
+	}
+
+	if concurrency < 0 {
+		return nil, &ParamsError{
+			Options: []string{"Concurrency"},
+			Reason:  "must not be negative",
+		}
+	}
+
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	if validator, ok := rev.(RevValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		if repo == nil || repo.Config == nil {
+			option := fmt.Sprintf("repos[%d]", i)
+			if repo != nil {
+				option += ".Config"
+			}
+
+			return nil, &ParamsError{
+				Options: []string{option},
+				Reason:  "must not be nil",
+			}
+		}
+
+		names[i] = repo.Name
+		if names[i] == "" {
+			names[i] = NewClient(repo.Config).config.Path
+		}
+	}
+
+	commits := make([][]*Commit, len(repos))
+	errors := make([]error, len(repos))
+
+	sem := make(chan struct{}, concurrency)
+	wg := &sync.WaitGroup{}
+
+	for i, repo := range repos {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, repo *Repository) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			commits[i], errors[i] = New(repo.Config).Log(rev, params)
+		}(i, repo)
+	}
+
+	wg.Wait()
+
+	result := &MultiLogResult{
+		Commits: []*RepositoryCommit{},
+		Errors:  []*RepositoryError{},
+	}
+
+	for i := range repos {
+		if errors[i] != nil {
+			result.Errors = append(result.Errors, &RepositoryError{
+				Repository: names[i],
+				Err:        errors[i],
+			})
+			continue
+		}
+
+		for _, commit := range commits[i] {
+			result.Commits = append(result.Commits, &RepositoryCommit{
+				Commit:     commit,
+				Repository: names[i],
+			})
+		}
+	}
+
+	reverse := params != nil && params.Reverse
+
+	// the order in a repository is kept on the same date
+	sort.SliceStable(result.Commits, func(i, j int) bool {
+		a, b := result.Commits[i].Committer.Date, result.Commits[j].Committer.Date
+		if reverse {
+			return a.Before(b)
+		}
+		return a.After(b)
+	})
+
+	return result, nil
+}
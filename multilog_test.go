@@ -0,0 +1,90 @@
+package gitlog
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupMultiLog(repos map[string][]int) func() {
+	for dir, days := range repos {
+		mkdirp(dir)
+		git("-C", dir, "init")
+		git("-C", dir, "config", "--local", "user.name", "authorname")
+		git("-C", dir, "config", "--local", "user.email", "mail@example.com")
+
+		for _, day := range days {
+			date := fmt.Sprintf("2018-01-%02dT00:00:00+0000", day)
+			cmd := exec.Command("git", "-C", dir, "commit", "--allow-empty", "-m", fmt.Sprintf("%s day %d", dir, day))
+			cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+			cmd.Run()
+		}
+	}
+
+	return func() {
+		for dir := range repos {
+			rimraf(dir)
+		}
+	}
+}
+
+func TestMultiLog(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setupMultiLog(map[string][]int{
+		".tmp-a": {1, 3, 4},
+		".tmp-b": {2, 5},
+	})
+	defer clear()
+
+	repos := []*Repository{
+		{Config: &Config{Path: ".tmp-a"}},
+		{Name: "missing", Config: &Config{Path: ".tmp-missing"}},
+		{Name: "b", Config: &Config{Path: ".tmp-b"}},
+	}
+
+	result, err := MultiLog(repos, nil, nil, &MultiLogParams{Concurrency: 1})
+	assert.Nil(err)
+
+	subjects := []string{}
+	names := []string{}
+	for _, commit := range result.Commits {
+		subjects = append(subjects, commit.Subject)
+		names = append(names, commit.Repository)
+	}
+
+	assert.Equal([]string{
+		".tmp-b day 5",
+		".tmp-a day 4",
+		".tmp-a day 3",
+		".tmp-b day 2",
+		".tmp-a day 1",
+	}, subjects)
+	assert.Equal([]string{"b", ".tmp-a", ".tmp-a", "b", ".tmp-a"}, names)
+
+	assert.Equal(1, len(result.Errors))
+	assert.Equal("missing", result.Errors[0].Repository)
+	assert.IsType(&NotRepositoryError{}, result.Errors[0].Err)
+
+	// shared RevArgs and Params
+	result, err = MultiLog(repos, &RevNumber{1}, &Params{Reverse: true}, nil)
+	assert.Nil(err)
+	assert.Equal(2, len(result.Commits))
+	assert.Equal(".tmp-a day 4", result.Commits[0].Subject)
+	assert.Equal(".tmp-b day 5", result.Commits[1].Subject)
+
+	_, err = MultiLog(repos, nil, nil, &MultiLogParams{Concurrency: -1})
+	assert.IsType(&ParamsError{}, err)
+
+	_, err = MultiLog(repos, nil, &Params{MergesOnly: true, IgnoreMerges: true}, nil)
+	assert.IsType(&ParamsError{}, err)
+
+	_, err = MultiLog(append(repos, nil), nil, nil, nil)
+	assert.Equal(`"repos[3]" must not be nil`, err.Error())
+
+	_, err = MultiLog(append(repos, &Repository{Name: "c"}), nil, nil, nil)
+	assert.Equal(`"repos[3].Config" must not be nil`, err.Error())
+}
@@ -0,0 +1,76 @@
+package gitlog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Order of the commits in git-log
+type Order int
+
+// List of Order
+const (
+	OrderDefault    Order = iota // reverse chronological order
+	OrderDate                    // alias for `--date-order`
+	OrderAuthorDate              // alias for `--author-date-order`
+	OrderTopo                    // alias for `--topo-order`
+)
+
+func (order Order) arg() string {
+	switch order {
+	case OrderDate:
+		return "--date-order"
+	case OrderAuthorDate:
+		return "--author-date-order"
+	case OrderTopo:
+		return "--topo-order"
+	}
+	return ""
+}
+
+// Params for getting git-log
+type Params struct {
+	MergesOnly           bool
+	IgnoreMerges         bool
+	Reverse              bool
+	FirstParent          bool
+	AncestryPath         bool
+	Order                Order
+	SimplifyByDecoration bool
+	Boundary             bool
+	Verify               bool // resolve refs before git-log, unknown refs are reported with RefError
+	Mailmap              bool // identities are mapped by .mailmap (`%aN`, `%aE`, `%cN` and `%cE`)
+}
+
+// ParamsError is returned when Params contains options that can not be used
+type ParamsError struct {
+	Options []string
+	Reason  string
+}
+
+func (e *ParamsError) Error() string {
+	return fmt.Sprintf("\"%s\" %s", strings.Join(e.Options, "\", \""), e.Reason)
+}
+
+// validate check for incompatible combinations of options
+func (params *Params) validate() error {
+	if params == nil {
+		return nil
+	}
+
+	if params.MergesOnly && params.IgnoreMerges {
+		return &ParamsError{
+			Options: []string{"MergesOnly", "IgnoreMerges"},
+			Reason:  "can not be used together",
+		}
+	}
+
+	if params.Order < OrderDefault || params.Order > OrderTopo {
+		return &ParamsError{
+			Options: []string{"Order"},
+			Reason:  fmt.Sprintf("is unknown order (%d)", params.Order),
+		}
+	}
+
+	return nil
+}
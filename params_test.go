@@ -0,0 +1,70 @@
+package gitlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamsValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	var params *Params
+	assert.Nil(params.validate())
+
+	assert.Nil((&Params{
+		MergesOnly:  true,
+		FirstParent: true,
+		Order:       OrderTopo,
+	}).validate())
+
+	err := (&Params{
+		MergesOnly:   true,
+		IgnoreMerges: true,
+	}).validate()
+	assert.IsType(&ParamsError{}, err)
+	assert.Equal([]string{"MergesOnly", "IgnoreMerges"}, err.(*ParamsError).Options)
+	assert.Equal("\"MergesOnly\", \"IgnoreMerges\" can not be used together", err.Error())
+
+	err = (&Params{
+		Order: Order(10),
+	}).validate()
+	assert.IsType(&ParamsError{}, err)
+	assert.Equal([]string{"Order"}, err.(*ParamsError).Options)
+}
+
+func TestParamsArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	git := &Client{}
+
+	args := git.buildArgs(nil, &Params{
+		FirstParent:          true,
+		AncestryPath:         true,
+		Order:                OrderAuthorDate,
+		SimplifyByDecoration: true,
+		Boundary:             true,
+	}, false)
+	assert.Equal([]string{
+		"--first-parent",
+		"--ancestry-path",
+		"--author-date-order",
+		"--simplify-by-decoration",
+		"--boundary",
+		"--",
+	}, args[2:])
+
+	table := map[Order]string{
+		OrderDate:       "--date-order",
+		OrderAuthorDate: "--author-date-order",
+		OrderTopo:       "--topo-order",
+	}
+
+	for order, expect := range table {
+		args = git.buildArgs(nil, &Params{Order: order}, false)
+		assert.Equal([]string{expect, "--"}, args[2:])
+	}
+
+	args = git.buildArgs(nil, &Params{}, false)
+	assert.Equal(3, len(args))
+}
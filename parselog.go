@@ -0,0 +1,413 @@
+package gitlog
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogFormat of the saved git-log output
+type LogFormat int
+
+// List of LogFormat
+const (
+	LogFormatAuto   LogFormat = iota // detected from the content
+	LogFormatGitLog                  // format used by GitLog.Log
+	LogFormatRaw                     // `git log --format=raw`
+	LogFormatFuller                  // `git log --format=fuller`, `medium` is also accepted
+)
+
+// ParseError is returned when the git-log output can not be parsed
+type ParseError struct {
+	Line   int // line number starting from 1
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
+
+// GitLogArgs returns the args of git-log whose output is parsed as LogFormatGitLog
+// e.g. `exec.Command("git", append([]string{"log"}, gitlog.GitLogArgs()...)...)`
+func GitLogArgs() []string {
+	return []string{"--no-decorate", "--pretty=" + logFormat}
+}
+
+// ParseLog parses the git-log output read from r
+// e.g. the output saved by build agents can be parsed offline.
+// `medium` has no committer, so Committer is the copy of Author to keep Commit in the same shape as the one returned by Log.
+// the diff and the stats after the message (e.g. `-p` and `--stat`) are ignored.
+func ParseLog(r io.Reader, format LogFormat) ([]*Commit, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	out := strings.Replace(string(content), "\r\n", "\n", -1)
+
+	if format == LogFormatAuto {
+		format = detectLogFormat(out)
+	}
+
+	switch format {
+	case LogFormatGitLog:
+		return parseGitLogFormat(out)
+	case LogFormatRaw, LogFormatFuller:
+		return parseMediumFormat(out, format)
+	}
+
+	return nil, &ParamsError{
+		Options: []string{"LogFormat"},
+		Reason:  fmt.Sprintf("is unknown format (%d)", format),
+	}
+}
+
+// detectLogFormat returns LogFormatFuller for the output which is not detected
+func detectLogFormat(out string) LogFormat {
+	if strings.Contains(out, separator) {
+		return LogFormatGitLog
+	}
+
+	lines := strings.Split(strings.TrimLeft(out, "\n"), "\n")
+	if len(lines) > 1 && strings.HasPrefix(lines[0], "commit ") && strings.HasPrefix(lines[1], "tree ") {
+		return LogFormatRaw
+	}
+
+	return LogFormatFuller
+}
+
+var gitLogFieldRegexps = map[string]*regexp.Regexp{
+	hashField:      regexp.MustCompile(`^[0-9a-f]+ [0-9a-f]+$`),
+	treeField:      regexp.MustCompile(`^[0-9a-f]+ [0-9a-f]+$`),
+	authorField:    regexp.MustCompile(`^[^<]*<[^>]*>\[\d+\]$`),
+	committerField: regexp.MustCompile(`^[^<]*<[^>]*>\[\d+\]$`),
+	tagField:       regexp.MustCompile(``),
+}
+
+// parseGitLogFormat checks the required fields before parser, because parser trusts the output of git
+func parseGitLogFormat(out string) ([]*Commit, error) {
+	records := strings.Split(out, separator)
+	line := 1 + strings.Count(records[0], "\n")
+
+	for _, record := range records[1:] {
+		found := map[string]bool{}
+
+		for _, segment := range strings.Split(record, delimiter) {
+			end := strings.Index(segment, ":")
+			if end < 0 {
+				return nil, &ParseError{Line: line, Reason: fmt.Sprintf("field \"%s\" has no name", segment)}
+			}
+
+			name := segment[:end]
+			if re, ok := gitLogFieldRegexps[name]; ok {
+				if !re.MatchString(segment[end+1:]) {
+					return nil, &ParseError{Line: line, Reason: fmt.Sprintf("field \"%s\" is malformed", name)}
+				}
+				found[name] = true
+			}
+		}
+
+		for name := range gitLogFieldRegexps {
+			if !found[name] {
+				return nil, &ParseError{Line: line, Reason: fmt.Sprintf("field \"%s\" is missing", name)}
+			}
+		}
+
+		line += strings.Count(record, "\n")
+	}
+
+	p := &parser{}
+	return p.parse(&out)
+}
+
+var fullerDateLayouts = []string{
+	"Mon Jan 2 15:04:05 2006 -0700", // default
+	"2006-01-02 15:04:05 -0700",     // iso
+	time.RFC3339,                    // iso-strict
+	time.RFC1123Z,                   // rfc
+}
+
+// parseMediumFormat parses the formats starting with `commit <hash>` line, the headers and the indented message
+func parseMediumFormat(out string, format LogFormat) ([]*Commit, error) {
+	commits := []*Commit{}
+	p := &parser{}
+
+	var commit *Commit
+	var message []string
+	inMessage := false
+	endMessage := false
+
+	flush := func() {
+		if commit == nil {
+			return
+		}
+
+		commit.Subject, commit.Body = splitMessage(message)
+		if commit.Committer == nil {
+			// medium has no committer
+			commit.Committer = &Committer{
+				Name:  commit.Author.Name,
+				Email: commit.Author.Email,
+				Date:  commit.Author.Date,
+			}
+		}
+		commit.Tag.Date = commit.Author.Date
+
+		commits = append(commits, commit)
+	}
+
+	lines := strings.Split(out, "\n")
+
+	for i, line := range lines {
+		lineNo := i + 1
+
+		if strings.HasPrefix(line, "commit ") {
+			if commit != nil && commit.Author == nil {
+				return nil, &ParseError{Line: lineNo, Reason: "previous commit has no author"}
+			}
+			flush()
+
+			var err error
+			commit, err = parseCommitLine(p, line[len("commit "):])
+			if err != nil {
+				return nil, &ParseError{Line: lineNo, Reason: err.Error()}
+			}
+
+			message = []string{}
+			inMessage = false
+			endMessage = false
+			continue
+		}
+
+		if commit == nil {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			return nil, &ParseError{Line: lineNo, Reason: "expected \"commit <hash>\""}
+		}
+
+		if endMessage {
+			continue
+		}
+
+		if inMessage {
+			// the message ends at the first line not indented, e.g. diff and stats
+			if strings.HasPrefix(line, "    ") {
+				message = append(message, line[4:])
+			} else if line == "" {
+				message = append(message, "")
+			} else {
+				endMessage = true
+			}
+			continue
+		}
+
+		if line == "" {
+			inMessage = true
+			continue
+		}
+
+		var err error
+		if format == LogFormatRaw {
+			err = parseRawHeader(commit, line)
+		} else {
+			err = parseFullerHeader(commit, line)
+		}
+
+		if err != nil {
+			return nil, &ParseError{Line: lineNo, Reason: err.Error()}
+		}
+	}
+
+	if commit != nil && commit.Author == nil {
+		return nil, &ParseError{Line: len(lines), Reason: "commit has no author"}
+	}
+	flush()
+
+	return commits, nil
+}
+
+var commitLineRegex = regexp.MustCompile(`^(?:([-<>=+]) ?)?([0-9a-f]{4,})(?: \(from [0-9a-f]+\))?(?: \((.*)\))?$`)
+
+// parseCommitLine parses `[<mark> ]<hash> [(from <hash>)] [(<decorations>)]`
+func parseCommitLine(p *parser, str string) (*Commit, error) {
+	res := commitLineRegex.FindStringSubmatch(str)
+	if res == nil {
+		return nil, fmt.Errorf("\"%s\" is not a commit hash", str)
+	}
+
+	commit := &Commit{
+		Hash: &Hash{Long: res[2], Short: shortHash(res[2])},
+		Tree: &Tree{},
+		Tag:  p.parseTag(&res[3]),
+	}
+
+	switch res[1] {
+	case "-":
+		commit.Boundary = true
+	case "=":
+		commit.Equivalent = true
+	case "<":
+		commit.Side = SideLeft
+	case ">":
+		commit.Side = SideRight
+	}
+
+	return commit, nil
+}
+
+func parseRawHeader(commit *Commit, line string) error {
+	// continuation of the multi-line header such as `gpgsig`
+	if strings.HasPrefix(line, " ") {
+		return nil
+	}
+
+	fields := strings.SplitN(line, " ", 2)
+	if len(fields) < 2 {
+		return fmt.Errorf("header \"%s\" has no value", line)
+	}
+
+	switch fields[0] {
+	case "tree":
+		commit.Tree = &Tree{Long: fields[1], Short: shortHash(fields[1])}
+	case "author":
+		name, email, date, err := parseRawIdentity(fields[1])
+		if err != nil {
+			return err
+		}
+		commit.Author = &Author{Name: name, Email: email, Date: date}
+	case "committer":
+		name, email, date, err := parseRawIdentity(fields[1])
+		if err != nil {
+			return err
+		}
+		commit.Committer = &Committer{Name: name, Email: email, Date: date}
+	}
+
+	return nil
+}
+
+// parseRawIdentity parses `Name <email> 1517138361 +0900`
+func parseRawIdentity(str string) (string, string, time.Time, error) {
+	name, email, rest, err := parseIdentity(str)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) != 2 {
+		return "", "", time.Time{}, fmt.Errorf("\"%s\" has no timestamp", str)
+	}
+
+	timestamp, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("\"%s\" is not a timestamp", fields[0])
+	}
+
+	zone, err := time.Parse("-0700", fields[1])
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("\"%s\" is not a timezone", fields[1])
+	}
+
+	return name, email, time.Unix(timestamp, 0).In(zone.Location()), nil
+}
+
+func parseFullerHeader(commit *Commit, line string) error {
+	end := strings.Index(line, ":")
+	if end < 0 {
+		return fmt.Errorf("header \"%s\" has no name", line)
+	}
+
+	value := strings.TrimSpace(line[end+1:])
+
+	switch line[:end] {
+	case "Author":
+		name, email, _, err := parseIdentity(value)
+		if err != nil {
+			return err
+		}
+		if commit.Author == nil {
+			commit.Author = &Author{}
+		}
+		commit.Author.Name, commit.Author.Email = name, email
+	case "Commit":
+		name, email, _, err := parseIdentity(value)
+		if err != nil {
+			return err
+		}
+		if commit.Committer == nil {
+			commit.Committer = &Committer{}
+		}
+		commit.Committer.Name, commit.Committer.Email = name, email
+	case "AuthorDate", "Date":
+		date, err := parseFullerDate(value)
+		if err != nil {
+			return err
+		}
+		if commit.Author == nil {
+			commit.Author = &Author{}
+		}
+		commit.Author.Date = date
+	case "CommitDate":
+		date, err := parseFullerDate(value)
+		if err != nil {
+			return err
+		}
+		if commit.Committer == nil {
+			commit.Committer = &Committer{}
+		}
+		commit.Committer.Date = date
+	}
+
+	return nil
+}
+
+func parseFullerDate(str string) (time.Time, error) {
+	for _, layout := range fullerDateLayouts {
+		if date, err := time.Parse(layout, str); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("\"%s\" is not a supported date format", str)
+}
+
+// parseIdentity parses `Name <email>` and returns the rest
+func parseIdentity(str string) (string, string, string, error) {
+	begin := strings.Index(str, "<")
+	end := strings.LastIndex(str, ">")
+	if begin < 0 || end < begin {
+		return "", "", "", fmt.Errorf("\"%s\" has no email", str)
+	}
+
+	return strings.TrimSpace(str[:begin]), str[begin+1 : end], str[end+1:], nil
+}
+
+// splitMessage returns the subject and the body in the same way as `%s` and `%b`
+func splitMessage(lines []string) (string, string) {
+	message := strings.Trim(strings.Join(lines, "\n"), "\n")
+	parts := strings.SplitN(message, "\n\n", 2)
+
+	subjectLines := strings.Split(parts[0], "\n")
+	for i, line := range subjectLines {
+		subjectLines[i] = strings.TrimSpace(line)
+	}
+	subject := strings.Join(subjectLines, " ")
+	if len(parts) < 2 {
+		return subject, ""
+	}
+
+	return subject, strings.TrimSpace(parts[1])
+}
+
+// shortHash abbreviates hash with the default length of git
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
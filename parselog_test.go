@@ -0,0 +1,182 @@
+package gitlog
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertSameCommits(assert *assert.Assertions, expected, actual []*Commit, tree bool) {
+	assert.Equal(len(expected), len(actual))
+
+	for i := range expected {
+		if i >= len(actual) {
+			return
+		}
+
+		assert.Equal(expected[i].Hash, actual[i].Hash)
+		assert.Equal(expected[i].Subject, actual[i].Subject)
+		assert.Equal(expected[i].Body, actual[i].Body)
+		assert.Equal(expected[i].Author.Name, actual[i].Author.Name)
+		assert.Equal(expected[i].Author.Email, actual[i].Author.Email)
+		assert.Equal(expected[i].Author.Date.Unix(), actual[i].Author.Date.Unix())
+		assert.Equal(expected[i].Committer.Date.Unix(), actual[i].Committer.Date.Unix())
+		assert.Equal(expected[i].Tag.Name, actual[i].Tag.Name)
+
+		if tree {
+			assert.Equal(expected[i].Tree, actual[i].Tree)
+		}
+	}
+}
+
+func TestParseLog(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	expected, err := NewClient(&Config{Path: ".tmp"}).Log(nil, nil)
+	assert.Nil(err)
+
+	// own format
+	out := git(append([]string{"-C", ".tmp", "log"}, GitLogArgs()...)...)
+	commits, err := ParseLog(strings.NewReader(out), LogFormatAuto)
+	assert.Nil(err)
+	assertSameCommits(assert, expected, commits, true)
+
+	// raw
+	out = git("-C", ".tmp", "log", "--format=raw", "--decorate")
+	commits, err = ParseLog(strings.NewReader(out), LogFormatAuto)
+	assert.Nil(err)
+	assertSameCommits(assert, expected, commits, true)
+
+	commits, err = ParseLog(strings.NewReader(out), LogFormatRaw)
+	assert.Nil(err)
+	assert.Equal(7, len(commits))
+
+	// fuller
+	out = git("-C", ".tmp", "log", "--format=fuller", "--decorate", "--stat")
+	commits, err = ParseLog(strings.NewReader(out), LogFormatAuto)
+	assert.Nil(err)
+	assertSameCommits(assert, expected, commits, false)
+	assert.Equal(&Tree{}, commits[0].Tree)
+
+	for _, date := range []string{"default", "iso", "iso-strict", "rfc"} {
+		out = git("-C", ".tmp", "log", "--format=fuller", "--date="+date)
+		commits, err = ParseLog(strings.NewReader(out), LogFormatFuller)
+		assert.Nil(err, date)
+		assert.Equal(expected[0].Author.Date.Unix(), commits[0].Author.Date.Unix(), date)
+	}
+
+	// diff and stats, the context line of 3 spaces is indented by 4 spaces
+	ioutil.WriteFile(filepath.Join(".tmp", "file.txt"), []byte("   context\nfoo\n"), 0644)
+	git("-C", ".tmp", "add", "file.txt")
+	git("-C", ".tmp", "commit", "-m", "feat(file): Add file")
+	ioutil.WriteFile(filepath.Join(".tmp", "file.txt"), []byte("   context\nbar\n"), 0644)
+	git("-C", ".tmp", "commit", "-am", "fix(file): Fix file\n\nbody")
+
+	for _, format := range []string{"raw", "fuller", "medium"} {
+		out = git("-C", ".tmp", "log", "--format="+format, "-p", "--stat", "-2")
+		commits, err = ParseLog(strings.NewReader(out), LogFormatAuto)
+		assert.Nil(err, format)
+		assert.Equal(2, len(commits), format)
+		assert.Equal("body", commits[0].Body, format)
+		assert.Equal("", commits[1].Body, format)
+	}
+
+	git("-C", ".tmp", "reset", "--hard", "HEAD~2")
+
+	// medium
+	out = git("-C", ".tmp", "log", "--format=medium", "--boundary", "v1.0.0..2.1.0")
+	commits, err = ParseLog(strings.NewReader(out), LogFormatAuto)
+	assert.Nil(err)
+	assert.Equal(4, len(commits))
+	assert.True(commits[3].Boundary)
+	assert.Equal(commits[0].Author.Date, commits[0].Committer.Date)
+
+	// empty
+	commits, err = ParseLog(strings.NewReader(""), LogFormatAuto)
+	assert.Nil(err)
+	assert.Empty(commits)
+}
+
+func TestParseLogRaw(t *testing.T) {
+	assert := assert.New(t)
+
+	out := `commit 51064a83516c60fdffd99a7d605d168298d91464 (HEAD -> master, tag: v1.2.0)
+tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904
+parent 6dccb5c65f984ec8857243017f506008683342c2
+author tsuyoshiwada <mail@example.com> 1517138361 +0900
+committer Committer Name <committer@example.com> 1517138400 -0500
+gpgsig -----BEGIN PGP SIGNATURE-----
+ 
+ iQEzBAABCAAdFiEE
+ -----END PGP SIGNATURE-----
+
+    feat(parser): Add
+    raw format
+    
+    This is body.
+      Indented line.
+
+commit 6dccb5c65f984ec8857243017f506008683342c2
+tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904
+author tsuyoshiwada <mail@example.com> 1517134427 +0000
+committer tsuyoshiwada <mail@example.com> 1517134427 +0000
+
+    chore(*): Initial commit
+`
+
+	commits, err := ParseLog(strings.NewReader(out), LogFormatAuto)
+	assert.Nil(err)
+	assert.Equal(2, len(commits))
+
+	commit := commits[0]
+	assert.Equal(&Hash{Long: "51064a83516c60fdffd99a7d605d168298d91464", Short: "51064a8"}, commit.Hash)
+	assert.Equal(&Tree{Long: "4b825dc642cb6eb9a060e54bf8d69288fbee4904", Short: "4b825dc"}, commit.Tree)
+	assert.Equal("v1.2.0", commit.Tag.Name)
+	assert.Equal("feat(parser): Add raw format", commit.Subject)
+	assert.Equal("This is body.\n  Indented line.", commit.Body)
+	assert.Equal("Committer Name", commit.Committer.Name)
+	assert.Equal("committer@example.com", commit.Committer.Email)
+	assert.Equal(int64(1517138400), commit.Committer.Date.Unix())
+	assert.Equal("2018-01-28 06:20:00 -0500", commit.Committer.Date.Format("2006-01-02 15:04:05 -0700"))
+	assert.Equal(commit.Author.Date, commit.Tag.Date)
+
+	assert.Equal("", commits[1].Tag.Name)
+	assert.Equal("", commits[1].Body)
+}
+
+func TestParseLogError(t *testing.T) {
+	assert := assert.New(t)
+
+	table := []struct {
+		format LogFormat
+		input  string
+		line   int
+	}{
+		{LogFormatAuto, "foo\n", 1},
+		{LogFormatAuto, "commit zzz\n", 1},
+		{LogFormatRaw, "commit abcdef\ntree abcdef\nauthor foo 123 +0000\n", 3},
+		{LogFormatRaw, "commit abcdef\ntree abcdef\nauthor foo <mail@example.com> abc +0000\n", 3},
+		{LogFormatRaw, "commit abcdef\ntree abcdef\n\n    subject\ncommit 123456\n", 5},
+		{LogFormatFuller, "commit abcdef\nAuthor: foo <mail@example.com>\nAuthorDate: yesterday\n", 3},
+		{LogFormatFuller, "commit abcdef\n\n    subject\n", 4},
+		{LogFormatGitLog, "@@__GIT_LOG_SEPARATOR__@@HASH:abcdef", 1},
+		{LogFormatGitLog, "\n@@__GIT_LOG_SEPARATOR__@@HASH:abcdef abc@@__GIT_LOG_DELIMITER__@@foo", 2},
+		{LogFormatGitLog, "@@__GIT_LOG_SEPARATOR__@@HASH:abcdef abc@@__GIT_LOG_DELIMITER__@@TREE:abcdef abc@@__GIT_LOG_DELIMITER__@@AUTHOR:foo<mail@example.com>[123]@@__GIT_LOG_DELIMITER__@@COMMITTER:foo<mail@example.com>[123]", 1},
+	}
+
+	for _, test := range table {
+		_, err := ParseLog(strings.NewReader(test.input), test.format)
+		if assert.IsType(&ParseError{}, err, test.input) {
+			assert.Equal(test.line, err.(*ParseError).Line, test.input)
+		}
+	}
+
+	_, err := ParseLog(strings.NewReader(""), LogFormat(10))
+	assert.IsType(&ParamsError{}, err)
+}
@@ -29,6 +29,7 @@ func (p *parser) parse(str *string) ([]*Commit, error) {
 func (p *parser) parseCommit(str *string) *Commit {
 	segments := strings.Split(*str, delimiter)
 	commit := &Commit{}
+	var rawAuthor, rawCommitter *Author
 
 	for _, segment := range segments {
 		endFieldType := strings.Index(segment, ":")
@@ -44,8 +45,14 @@ func (p *parser) parseCommit(str *string) *Commit {
 			commit.Author = p.parseAuthor(&content)
 		case committerField:
 			commit.Committer = p.parseCommitter(&content)
+		case rawAuthorField:
+			rawAuthor = p.parseAuthor(&content)
+		case rawCommitterField:
+			rawCommitter = p.parseAuthor(&content)
 		case tagField:
 			commit.Tag = p.parseTag(&content)
+		case markField:
+			p.parseMark(&content, commit)
 		case subjectField:
 			commit.Subject = p.parseSubject(&content)
 		case bodyField:
@@ -55,6 +62,16 @@ func (p *parser) parseCommit(str *string) *Commit {
 
 	commit.Tag.Date = commit.Author.Date
 
+	if rawAuthor != nil {
+		commit.Author.RawName = rawAuthor.Name
+		commit.Author.RawEmail = rawAuthor.Email
+	}
+
+	if rawCommitter != nil {
+		commit.Committer.RawName = rawCommitter.Name
+		commit.Committer.RawEmail = rawCommitter.Email
+	}
+
 	return commit
 }
 
@@ -89,9 +106,9 @@ func (p *parser) parseAuthor(str *string) *Author {
 	timestamp, _ := strconv.Atoi(s[beginDate+1 : endDate])
 
 	return &Author{
-		name,
-		email,
-		time.Unix(int64(timestamp), 0),
+		Name:  name,
+		Email: email,
+		Date:  time.Unix(int64(timestamp), 0),
 	}
 }
 
@@ -99,9 +116,9 @@ func (p *parser) parseCommitter(str *string) *Committer {
 	author := p.parseAuthor(str)
 
 	return &Committer{
-		author.Name,
-		author.Email,
-		author.Date,
+		Name:  author.Name,
+		Email: author.Email,
+		Date:  author.Date,
 	}
 }
 
@@ -120,6 +137,19 @@ func (p *parser) parseTag(str *string) *Tag {
 	return tag
 }
 
+func (p *parser) parseMark(str *string, commit *Commit) {
+	commit.Boundary = *str == "-"
+	commit.Equivalent = *str == "="
+
+	// `--left-right` without `--cherry-mark`, `>` is also printed for every commit without `--left-right`
+	switch *str {
+	case "<":
+		commit.Side = SideLeft
+	case ">":
+		commit.Side = SideRight
+	}
+}
+
 func (*parser) convNewline(str *string) string {
 	nl := "\n"
 
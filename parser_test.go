@@ -108,3 +108,25 @@ bar`,
 	assert.Nil(err)
 	assert.Equal(table, commits)
 }
+
+func TestParserMark(t *testing.T) {
+	assert := assert.New(t)
+
+	commitLog := `@@__GIT_LOG_SEPARATOR__@@HASH:6dccb5c65f984ec8857243017f506008683342c2 6dccb5c@@__GIT_LOG_DELIMITER__@@TREE:4b825dc642cb6eb9a060e54bf8d69288fbee4904 4b825dc@@__GIT_LOG_DELIMITER__@@AUTHOR:tsuyoshiwada<mail@example.com>[1517134427]@@__GIT_LOG_DELIMITER__@@COMMITTER:tsuyoshiwada<mail@example.com>[1517134427]@@__GIT_LOG_DELIMITER__@@TAG:@@__GIT_LOG_DELIMITER__@@MARK:>@@__GIT_LOG_DELIMITER__@@SUBJECT:docs(readme): Test commit@@__GIT_LOG_DELIMITER__@@BODY:
+@@__GIT_LOG_SEPARATOR__@@HASH:806512fe97c9c3397b7ed30c0b4076032112f697 806512f@@__GIT_LOG_DELIMITER__@@TREE:4b825dc642cb6eb9a060e54bf8d69288fbee4904 4b825dc@@__GIT_LOG_DELIMITER__@@AUTHOR:tsuyoshiwada<mail@example.com>[1517122160]@@__GIT_LOG_DELIMITER__@@COMMITTER:tsuyoshiwada<mail@example.com>[1517122160]@@__GIT_LOG_DELIMITER__@@TAG:tag: v0.2.1@@__GIT_LOG_DELIMITER__@@MARK:-@@__GIT_LOG_DELIMITER__@@SUBJECT:chore(*): Initial commit@@__GIT_LOG_DELIMITER__@@BODY:`
+
+	parser := &parser{}
+	commits, err := parser.parse(&commitLog)
+
+	assert.Nil(err)
+	assert.Equal(2, len(commits))
+	assert.False(commits[0].Boundary)
+	assert.True(commits[1].Boundary)
+	assert.Equal(SideRight, commits[0].Side)
+	assert.Equal(SideNone, commits[1].Side)
+	assert.True(sidesMarked(commits))
+
+	// `%m` of `--cherry-mark` does not have the side
+	commits[0].Side = SideNone
+	assert.False(sidesMarked(commits))
+}
@@ -0,0 +1,148 @@
+package gitlog
+
+import (
+	"path"
+	"strings"
+)
+
+const refsFormat = separator +
+	"%(refname)" + delimiter +
+	"%(objectname) %(objectname:short)" + delimiter +
+	"%(*objectname) %(*objectname:short)" + delimiter +
+	"%(symref)"
+
+// RefType of ref
+type RefType int
+
+// List of RefType
+const (
+	RefTypeBranch RefType = iota + 1 // refs/heads
+	RefTypeRemote                    // refs/remotes
+	RefTypeTag                       // refs/tags
+)
+
+var refPrefixes = map[RefType]string{
+	RefTypeBranch: "refs/heads/",
+	RefTypeRemote: "refs/remotes/",
+	RefTypeTag:    "refs/tags/",
+}
+
+// RefsParams for getting refs
+type RefsParams struct {
+	Types      []RefType // all types if empty
+	Pattern    string    // glob pattern of short name (e.g. `v1.*`, `origin/*`)
+	Contains   string    // only refs containing the commit (`--contains <commit>`)
+	NoContains string    // only refs not containing the commit (`--no-contains <commit>`)
+	Merged     string    // only refs reachable from the commit (`--merged <commit>`)
+	NoMerged   string    // only refs not reachable from the commit (`--no-merged <commit>`)
+}
+
+// Ref is a branch, remote-tracking branch or tag
+type Ref struct {
+	Name   string // e.g. `master`, `origin/master`, `v1.0.0`
+	Ref    string // e.g. `refs/heads/master`
+	Type   RefType
+	Commit *Hash // target commit, annotated tags are peeled
+}
+
+// Refs returns the refs filtered by reachability, sorted by refname
+// e.g. the tags and branches which contain the commit
+func (gitLog *Client) Refs(params *RefsParams) ([]*Ref, error) {
+	if params == nil {
+		params = &RefsParams{}
+	}
+
+	filters := []struct {
+		option string
+		rev    string
+	}{
+		{"--contains", params.Contains},
+		{"--no-contains", params.NoContains},
+		{"--merged", params.Merged},
+		{"--no-merged", params.NoMerged},
+	}
+
+	args := []string{"--format=" + refsFormat}
+
+	for _, filter := range filters {
+		if filter.rev == "" {
+			continue
+		}
+
+		if err := ValidateRevision(filter.rev); err != nil {
+			return nil, err
+		}
+
+		args = append(args, filter.option+"="+filter.rev)
+	}
+
+	types := params.Types
+	if len(types) == 0 {
+		types = []RefType{RefTypeBranch, RefTypeRemote, RefTypeTag}
+	}
+
+	for _, typ := range types {
+		prefix, ok := refPrefixes[typ]
+		if !ok {
+			return nil, &ParamsError{
+				Options: []string{"Types"},
+				Reason:  "contains unknown ref type",
+			}
+		}
+		args = append(args, strings.TrimSuffix(prefix, "/"))
+	}
+
+	// Check pattern before running git
+	if _, err := path.Match(params.Pattern, ""); err != nil {
+		return nil, err
+	}
+
+	if err := gitLog.prepare(); err != nil {
+		return nil, err
+	}
+
+	out, err := gitLog.exec("for-each-ref", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := []*Ref{}
+
+	for _, record := range strings.Split(out, separator)[1:] {
+		fields := strings.Split(record, delimiter)
+		for len(fields) < 4 {
+			fields = append(fields, "")
+		}
+
+		// symbolic refs such as `origin/HEAD`
+		if strings.TrimSpace(fields[3]) != "" {
+			continue
+		}
+
+		ref := &Ref{
+			Ref:    fields[0],
+			Commit: parseTagHash(fields[2]),
+		}
+
+		if ref.Commit == nil {
+			ref.Commit = parseTagHash(fields[1])
+		}
+
+		for typ, prefix := range refPrefixes {
+			if strings.HasPrefix(ref.Ref, prefix) {
+				ref.Type = typ
+				ref.Name = strings.TrimPrefix(ref.Ref, prefix)
+			}
+		}
+
+		if params.Pattern != "" {
+			if ok, _ := path.Match(params.Pattern, ref.Name); !ok {
+				continue
+			}
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs, nil
+}
@@ -0,0 +1,99 @@
+package gitlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func refNames(refs []*Ref) []string {
+	names := []string{}
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
+func TestRefs(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setupBranches()
+	defer clear()
+
+	git("-C", ".tmp", "tag", "-a", "v3.0.0", "-m", "Release v3.0.0", "v3.0.0-rc.10")
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	refs, err := gitLog.Refs(nil)
+	assert.Nil(err)
+	assert.Equal([]string{
+		"feature",
+		"master",
+		"topic",
+		"origin/master",
+		"2.1.0",
+		"3.6.4-beta.12",
+		"v1.0.0",
+		"v3.0.0",
+		"v3.0.0-rc.10",
+	}, refNames(refs))
+
+	assert.Equal("refs/heads/feature", refs[0].Ref)
+	assert.Equal(RefTypeBranch, refs[0].Type)
+	assert.Equal(RefTypeRemote, refs[3].Type)
+	assert.Equal(RefTypeTag, refs[7].Type)
+
+	// annotated tag is peeled
+	assert.Equal(refs[8].Commit, refs[7].Commit)
+	assert.Len(refs[7].Commit.Short, 7)
+
+	refs, err = gitLog.Refs(&RefsParams{Contains: "topic"})
+	assert.Nil(err)
+	assert.Equal([]string{
+		"feature",
+		"master",
+		"topic",
+		"origin/master",
+		"2.1.0",
+		"3.6.4-beta.12",
+		"v3.0.0",
+		"v3.0.0-rc.10",
+	}, refNames(refs))
+
+	refs, err = gitLog.Refs(&RefsParams{
+		Types:      []RefType{RefTypeTag},
+		Contains:   "v1.0.0",
+		NoContains: "v3.0.0",
+	})
+	assert.Nil(err)
+	assert.Equal([]string{"2.1.0", "v1.0.0"}, refNames(refs))
+
+	refs, err = gitLog.Refs(&RefsParams{
+		Types:  []RefType{RefTypeTag},
+		Merged: "2.1.0",
+	})
+	assert.Nil(err)
+	assert.Equal([]string{"2.1.0", "v1.0.0"}, refNames(refs))
+
+	refs, err = gitLog.Refs(&RefsParams{
+		Types:    []RefType{RefTypeBranch, RefTypeRemote},
+		NoMerged: "master",
+	})
+	assert.Nil(err)
+	assert.Equal([]string{"feature"}, refNames(refs))
+
+	refs, err = gitLog.Refs(&RefsParams{Pattern: "v3.*"})
+	assert.Nil(err)
+	assert.Equal([]string{"v3.0.0", "v3.0.0-rc.10"}, refNames(refs))
+
+	_, err = gitLog.Refs(&RefsParams{Types: []RefType{0}})
+	assert.IsType(&ParamsError{}, err)
+
+	_, err = gitLog.Refs(&RefsParams{Contains: "--all"})
+	assert.IsType(&InvalidRevError{}, err)
+
+	_, err = gitLog.Refs(&RefsParams{Contains: "notfound"})
+	assert.NotNil(err)
+}
@@ -0,0 +1,96 @@
+package gitlog
+
+import "fmt"
+
+// ReleaseParams for getting the release range
+type ReleaseParams struct {
+	SkipPrerelease bool // previous release is searched from the stable versions
+}
+
+// ReleaseRange returns RevArgs of the commits since the previous release
+// the previous release is the greatest semantic version tag reachable from ref, which is less than ref.
+// ref must be a tag, or empty or HEAD for the range of unreleased commits.
+// if there is no previous release, all commits reachable from ref are returned.
+func (gitLog *Client) ReleaseRange(ref string, params *ReleaseParams) (RevArgs, error) {
+	if params == nil {
+		params = &ReleaseParams{}
+	}
+
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	if err := ValidateRevision(ref); err != nil {
+		return nil, err
+	}
+
+	if err := gitLog.prepare(); err != nil {
+		return nil, err
+	}
+
+	var current *Version
+
+	if ref != "HEAD" {
+		tags, err := gitLog.tags(&TagsParams{})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tag := range tags {
+			if tag.Name == ref {
+				if tag.Version == nil {
+					return nil, fmt.Errorf("\"%s\" is not semantic version", ref)
+				}
+				current = tag.Version
+			}
+		}
+
+		if current == nil {
+			// branches and commits do not have the version to compare
+			if _, err := gitLog.exec("rev-parse", "--verify", "--quiet", ref+"^{commit}"); err == nil {
+				return nil, &ParamsError{
+					Options: []string{ref},
+					Reason:  "is not a tag, a tag or HEAD is required",
+				}
+			}
+			return nil, &RefError{Missing: []string{ref}}
+		}
+	}
+
+	filter := TagFilterAll
+	if params.SkipPrerelease {
+		filter = TagFilterStable
+	}
+
+	tags, err := gitLog.tags(&TagsParams{
+		Merged: ref,
+		Filter: filter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var previous *Tag
+
+	for _, tag := range tags {
+		if tag.Version == nil || tag.Name == ref {
+			continue
+		}
+
+		if current != nil && tag.Version.Compare(current) >= 0 {
+			continue
+		}
+
+		// tags are sorted in ascending order
+		previous = tag
+	}
+
+	if previous == nil {
+		return &Rev{Ref: ref}, nil
+	}
+
+	return &RevRange{
+		Old: previous.Name,
+		New: ref,
+	}, nil
+}
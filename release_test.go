@@ -0,0 +1,103 @@
+package gitlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseRange(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setupTags()
+	defer clear()
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	table := []struct {
+		ref    string
+		params *ReleaseParams
+		expect RevArgs
+	}{
+		{"v1.0.0", nil, &Rev{Ref: "v1.0.0"}},
+		{"2.1.0", nil, &RevRange{Old: "v1.0.0", New: "2.1.0"}},
+		{"v3.0.0-rc.9", nil, &RevRange{Old: "2.1.0", New: "v3.0.0-rc.9"}},
+		{"v3.0.0", nil, &RevRange{Old: "v3.0.0-rc.10", New: "v3.0.0"}},
+		{"v3.0.0", &ReleaseParams{SkipPrerelease: true}, &RevRange{Old: "2.1.0", New: "v3.0.0"}},
+		{"3.6.4-beta.12", nil, &RevRange{Old: "v3.0.0", New: "3.6.4-beta.12"}},
+		{"", nil, &RevRange{Old: "3.6.4-beta.12", New: "HEAD"}},
+		{"HEAD", &ReleaseParams{SkipPrerelease: true}, &RevRange{Old: "v3.0.0", New: "HEAD"}},
+	}
+
+	for _, expect := range table {
+		rev, err := gitLog.ReleaseRange(expect.ref, expect.params)
+		assert.Nil(err, expect.ref)
+		assert.Equal(expect.expect, rev, expect.ref)
+	}
+
+	rev, _ := gitLog.ReleaseRange("v3.0.0", &ReleaseParams{SkipPrerelease: true})
+	commits, err := gitLog.Log(rev, nil)
+	assert.Nil(err)
+	assert.Equal(2, len(commits))
+	assert.Equal("style(*): Run GoFmt", commits[0].Subject)
+	assert.Equal("fix(logger): Fix bar function", commits[1].Subject)
+}
+
+func TestReleaseRangeUnreachable(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setupTags()
+	defer clear()
+
+	// greater version on the other branch is not the previous release
+	git("-C", ".tmp", "checkout", "-b", "next", "v1.0.0")
+	git("-C", ".tmp", "commit", "--allow-empty", "-m", "feat(*): Next")
+	git("-C", ".tmp", "tag", "v9.0.0")
+	git("-C", ".tmp", "checkout", "master")
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	rev, err := gitLog.ReleaseRange("", nil)
+	assert.Nil(err)
+	assert.Equal(&RevRange{Old: "3.6.4-beta.12", New: "HEAD"}, rev)
+
+	rev, err = gitLog.ReleaseRange("v9.0.0", nil)
+	assert.Nil(err)
+	assert.Equal(&RevRange{Old: "v1.0.0", New: "v9.0.0"}, rev)
+}
+
+func TestReleaseRangeError(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setupTags()
+	defer clear()
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	rev, err := gitLog.ReleaseRange("latest", nil)
+	assert.Nil(rev)
+	assert.Contains(err.Error(), "is not semantic version")
+
+	rev, err = gitLog.ReleaseRange("v0.0.1", nil)
+	assert.Nil(rev)
+	assert.Equal(&RefError{Missing: []string{"v0.0.1"}}, err)
+
+	for _, ref := range []string{"master", "HEAD~1"} {
+		rev, err = gitLog.ReleaseRange(ref, nil)
+		assert.Nil(rev)
+		assert.Equal(&ParamsError{
+			Options: []string{ref},
+			Reason:  "is not a tag, a tag or HEAD is required",
+		}, err)
+	}
+
+	rev, err = gitLog.ReleaseRange("--output=/tmp/x", nil)
+	assert.Nil(rev)
+	assert.IsType(&InvalidRevError{}, err)
+}
@@ -0,0 +1,298 @@
+package gitlog
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// RevParseError is returned when the revision expression is malformed
+type RevParseError struct {
+	Expr   string
+	Offset int // byte offset in Expr
+	Reason string
+}
+
+func (e *RevParseError) Error() string {
+	return fmt.Sprintf("\"%s\" is invalid revision at %d: %s", e.Expr, e.Offset, e.Reason)
+}
+
+type revToken struct {
+	value  string
+	offset int
+}
+
+// ParseRev parses the revision expression into RevArgs
+//
+//	"<ref>"                  -> Rev
+//	"<ref>..<ref>"           -> RevRange
+//	"<ref>...<ref>"          -> RevSymmetric
+//	"--all"                  -> RevAll
+//	"<ref> ^<ref> --not ..." -> RevSet
+//
+// The expression made from `Args()` of Rev, RevRange, RevSymmetric, RevAll and RevSet is parsed into an equivalent value,
+// which has the same `Args()`. It is not always the same value, e.g. `RevSet{Include: []string{"a"}}` is parsed into `Rev{Ref: "a"}`.
+// The options of the other RevArgs (e.g. `-n`, `--skip`, `--since` and `--until`) are not supported.
+func ParseRev(expr string) (RevArgs, error) {
+	p := &revParser{expr: expr}
+	return p.parse()
+}
+
+type revParser struct {
+	expr string
+}
+
+func (p *revParser) error(offset int, format string, a ...interface{}) error {
+	return &RevParseError{
+		Expr:   p.expr,
+		Offset: offset,
+		Reason: fmt.Sprintf(format, a...),
+	}
+}
+
+func (p *revParser) tokenize() []*revToken {
+	tokens := []*revToken{}
+	begin := -1
+
+	for i, r := range p.expr {
+		if unicode.IsSpace(r) {
+			if begin >= 0 {
+				tokens = append(tokens, &revToken{p.expr[begin:i], begin})
+				begin = -1
+			}
+		} else if begin < 0 {
+			begin = i
+		}
+	}
+
+	if begin >= 0 {
+		tokens = append(tokens, &revToken{p.expr[begin:], begin})
+	}
+
+	return tokens
+}
+
+func (p *revParser) parse() (RevArgs, error) {
+	tokens := p.tokenize()
+	if len(tokens) == 0 {
+		return nil, p.error(0, "empty revision")
+	}
+
+	set := &RevSet{}
+	var symmetric *RevSymmetric
+	var symmetricToken *revToken
+	var flags []*revToken
+	var ranges []*RevRange
+	var excludes []string // `--exclude` waiting for the next glob
+	not := false
+	refs := 0
+
+	for _, token := range tokens {
+		value := token.value
+
+		switch {
+		case value == "--not":
+			not = !not
+
+		case value == "--all":
+			p.appendGlob(set, &RefGlob{Exclude: excludes}, not)
+			excludes = nil
+
+		case value == "--left-right" || value == "--cherry-mark" || value == "--cherry-pick":
+			flags = append(flags, token)
+
+		case strings.HasPrefix(value, "--exclude="):
+			excludes = append(excludes, value[len("--exclude="):])
+
+		case p.isPattern(value, "--branches"), p.isPattern(value, "--tags"), p.isPattern(value, "--remotes"):
+			p.appendGlob(set, p.parseGlob(value, excludes), not)
+			excludes = nil
+
+		case strings.HasPrefix(value, "-"):
+			return nil, p.error(token.offset, "unknown option %s", value)
+
+		case strings.Contains(value, "..."):
+			if symmetric != nil {
+				return nil, p.error(token.offset, "symmetric difference can be specified only once")
+			}
+			left, right, err := p.parseRange(token, "...")
+			if err != nil {
+				return nil, err
+			}
+			symmetric = &RevSymmetric{Left: left, Right: right}
+			symmetricToken = token
+
+		case strings.Contains(value, ".."):
+			old, new, err := p.parseRange(token, "..")
+			if err != nil {
+				return nil, err
+			}
+			ranges = append(ranges, &RevRange{Old: old, New: new})
+			refs++
+
+			if old == "" {
+				old = "HEAD"
+			}
+			if new == "" {
+				new = "HEAD"
+			}
+			p.appendRef(set, old, !not)
+			p.appendRef(set, new, not)
+
+		case strings.HasPrefix(value, "^"):
+			if len(value) == 1 {
+				return nil, p.error(token.offset+1, "missing ref after '^'")
+			}
+			if err := p.validate(value[1:], token.offset+1); err != nil {
+				return nil, err
+			}
+			p.appendRef(set, value[1:], !not)
+			refs++
+
+		default:
+			if err := p.validate(value, token.offset); err != nil {
+				return nil, err
+			}
+			p.appendRef(set, value, not)
+			refs++
+		}
+	}
+
+	patterns := len(set.Branches) + len(set.Tags) + len(set.Remotes) + len(set.Globs) + len(set.Not)
+
+	if len(excludes) > 0 {
+		return nil, p.error(0, "--exclude requires --all, --branches, --tags or --remotes")
+	}
+
+	if len(tokens) == 1 && tokens[0].value == "--all" {
+		return &RevAll{}, nil
+	}
+
+	if symmetric != nil {
+		if refs > 0 || patterns > 0 || not {
+			return nil, p.error(symmetricToken.offset, "symmetric difference can not be combined with other revisions")
+		}
+
+		for _, flag := range flags {
+			switch flag.value {
+			case "--cherry-mark":
+				symmetric.CherryMark = true
+			case "--cherry-pick":
+				symmetric.CherryPick = true
+			}
+		}
+
+		return symmetric, nil
+	}
+
+	if len(flags) > 0 {
+		return nil, p.error(flags[0].offset, "%s requires symmetric difference", flags[0].value)
+	}
+
+	if refs == 0 && patterns == 0 {
+		return nil, p.error(0, "no revision")
+	}
+
+	// simple forms
+	if len(tokens) == 1 {
+		if len(ranges) == 1 {
+			return ranges[0], nil
+		}
+		if len(set.Include) == 1 {
+			return &Rev{Ref: set.Include[0]}, nil
+		}
+	}
+
+	return set, nil
+}
+
+func (p *revParser) isPattern(value, option string) bool {
+	return value == option || strings.HasPrefix(value, option+"=")
+}
+
+func (p *revParser) parseGlob(value string, excludes []string) *RefGlob {
+	glob := &RefGlob{Exclude: excludes}
+
+	if i := strings.Index(value, "="); i >= 0 {
+		glob.Pattern = value[i+1:]
+	}
+
+	for typ, option := range refGlobOptions {
+		if p.isPattern(value, option) {
+			glob.Type = typ
+		}
+	}
+
+	return glob
+}
+
+// appendGlob uses the shorthand fields of RevSet for the glob without excludes
+func (p *revParser) appendGlob(set *RevSet, glob *RefGlob, not bool) {
+	switch {
+	case not:
+		set.Not = append(set.Not, glob)
+	case len(glob.Exclude) > 0 || glob.Type == 0:
+		set.Globs = append(set.Globs, glob)
+	case glob.Type == RefTypeBranch:
+		set.Branches = append(set.Branches, glob.Pattern)
+	case glob.Type == RefTypeTag:
+		set.Tags = append(set.Tags, glob.Pattern)
+	case glob.Type == RefTypeRemote:
+		set.Remotes = append(set.Remotes, glob.Pattern)
+	}
+}
+
+func (p *revParser) appendRef(set *RevSet, ref string, exclude bool) {
+	if exclude {
+		set.Exclude = append(set.Exclude, ref)
+	} else {
+		set.Include = append(set.Include, ref)
+	}
+}
+
+func (p *revParser) parseRange(token *revToken, dots string) (string, string, error) {
+	value := token.value
+
+	if strings.HasPrefix(value, "^") {
+		return "", "", p.error(token.offset, "'^' can not be used with range")
+	}
+
+	i := strings.Index(value, dots)
+	left := value[:i]
+	right := value[i+len(dots):]
+
+	if left == "" && right == "" {
+		return "", "", p.error(token.offset, "range requires at least one side")
+	}
+
+	if left != "" {
+		if err := p.validate(left, token.offset); err != nil {
+			return "", "", err
+		}
+	}
+
+	if right != "" {
+		if err := p.validate(right, token.offset+i+len(dots)); err != nil {
+			return "", "", err
+		}
+	}
+
+	return left, right, nil
+}
+
+// validate checks the syntax of a single revision such as `HEAD~5`, `v1.0.0^{commit}` or `@{2.weeks.ago}`
+func (p *revParser) validate(rev string, offset int) error {
+	if i, reason := checkRevision(rev); reason != "" {
+		return p.error(offset+i, "%s", reason)
+	}
+	return nil
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,147 @@
+package gitlog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRev(t *testing.T) {
+	assert := assert.New(t)
+
+	table := []struct {
+		expr   string
+		expect RevArgs
+	}{
+		{"HEAD", &Rev{Ref: "HEAD"}},
+		{" HEAD~5 ", &Rev{Ref: "HEAD~5"}},
+		{"v1.0.0^{commit}", &Rev{Ref: "v1.0.0^{commit}"}},
+		{"master^2~3", &Rev{Ref: "master^2~3"}},
+		{"@{2.weeks.ago}", &Rev{Ref: "@{2.weeks.ago}"}},
+		{"master@{upstream}", &Rev{Ref: "master@{upstream}"}},
+		{"v1.0.0..HEAD", &RevRange{Old: "v1.0.0", New: "HEAD"}},
+		{"v1.0.0..", &RevRange{Old: "v1.0.0", New: ""}},
+		{"..feature/a", &RevRange{Old: "", New: "feature/a"}},
+		{"main...feature", &RevSymmetric{Left: "main", Right: "feature"}},
+		{"--cherry-mark main...feature", &RevSymmetric{Left: "main", Right: "feature", CherryMark: true}},
+		{"--all", &RevAll{}},
+		{"^old new", &RevSet{Include: []string{"new"}, Exclude: []string{"old"}}},
+		{"^old", &RevSet{Exclude: []string{"old"}}},
+		{"a b --not c d", &RevSet{Include: []string{"a", "b"}, Exclude: []string{"c", "d"}}},
+		{"--not c --not a", &RevSet{Include: []string{"a"}, Exclude: []string{"c"}}},
+		{"v1..v2 topic", &RevSet{Include: []string{"v2", "topic"}, Exclude: []string{"v1"}}},
+		{"--not v1..v2", &RevSet{Include: []string{"v1"}, Exclude: []string{"v2"}}},
+		{"..v2 x", &RevSet{Include: []string{"v2", "x"}, Exclude: []string{"HEAD"}}},
+		{
+			"--exclude=*-rc --branches=release/* --tags ^master",
+			&RevSet{
+				Globs:   []*RefGlob{{Type: RefTypeBranch, Pattern: "release/*", Exclude: []string{"*-rc"}}},
+				Tags:    []string{""},
+				Exclude: []string{"master"},
+			},
+		},
+		{
+			"--exclude=wip/* --all --not --remotes=origin/*",
+			&RevSet{
+				Globs: []*RefGlob{{Exclude: []string{"wip/*"}}},
+				Not:   []*RefGlob{{Type: RefTypeRemote, Pattern: "origin/*"}},
+			},
+		},
+		{"--all ^master", &RevSet{Globs: []*RefGlob{{}}, Exclude: []string{"master"}}},
+		{"master --not --branches", &RevSet{Include: []string{"master"}, Not: []*RefGlob{{Type: RefTypeBranch}}}},
+	}
+
+	for _, expect := range table {
+		rev, err := ParseRev(expect.expr)
+		assert.Nil(err, expect.expr)
+		assert.Equal(expect.expect, rev, expect.expr)
+
+		// round-trip
+		rev, err = ParseRev(strings.Join(rev.Args(), " "))
+		assert.Nil(err, expect.expr)
+		assert.Equal(expect.expect, rev, expect.expr)
+	}
+}
+
+func TestParseRevRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	table := []RevArgs{
+		&Rev{Ref: "HEAD~1"},
+		&RevRange{Old: "v0.0.1", New: "v0.1.2"},
+		&RevSymmetric{Left: "a", Right: "b", CherryPick: true},
+		&RevAll{},
+		&RevSet{
+			Include:  []string{"feature/a", "feature/b"},
+			Exclude:  []string{"master", "v1.0.0"},
+			Branches: []string{"release/*", ""},
+			Remotes:  []string{"origin/*"},
+			Ignore:   []string{"*-wip", "tmp/*"},
+		},
+		&RevSet{Include: []string{"a"}},
+		&RevSet{
+			Include: []string{"master"},
+			Globs:   []*RefGlob{{Type: RefTypeTag, Pattern: "v*", Exclude: []string{"v0.*"}}},
+			Not:     []*RefGlob{{Exclude: []string{"tmp/*"}}},
+		},
+	}
+
+	// the result is equivalent, e.g. Ignore is parsed into Globs
+	for _, expect := range table {
+		rev, err := ParseRev(strings.Join(expect.Args(), " "))
+		assert.Nil(err)
+		assert.Equal(expect.Args(), rev.Args())
+	}
+}
+
+func TestParseRevError(t *testing.T) {
+	assert := assert.New(t)
+
+	table := []struct {
+		expr   string
+		offset int
+		reason string
+	}{
+		{"", 0, "empty revision"},
+		{"   ", 0, "empty revision"},
+		{"--not", 0, "no revision"},
+		{"..", 0, "range requires at least one side"},
+		{"...", 0, "range requires at least one side"},
+		{"a....b", 4, "unexpected '.'"},
+		{"a..b..c", 5, "unexpected '.'"},
+		{"^", 1, "missing ref after '^'"},
+		{"^a..b", 0, "'^' can not be used with range"},
+		{"~1", 0, "missing ref before '~'"},
+		{"HEAD~x", 5, "unexpected 'x'"},
+		{"HEAD^{commit", 5, "unclosed '^{'"},
+		{"HEAD@{1", 5, "unclosed '@{'"},
+		{"HEAD@{}", 5, "empty '@{}'"},
+		{"HEAD:README.md", 4, "path and search syntax are not supported"},
+		{"master .hidden", 7, "unexpected '.'"},
+		{"a refs/.x", 7, "unexpected '.'"},
+		{"master --output=/tmp/x", 7, "unknown option --output=/tmp/x"},
+		{"-n", 0, "unknown option -n"},
+		{"--all a...b", 6, "symmetric difference can not be combined with other revisions"},
+		{"a...b c", 0, "symmetric difference can not be combined with other revisions"},
+		{"a...b c...d", 6, "symmetric difference can be specified only once"},
+		{"a --left-right", 2, "--left-right requires symmetric difference"},
+		{"--exclude=x master", 0, "--exclude requires --all, --branches, --tags or --remotes"},
+		{"--branches --exclude=x", 0, "--exclude requires --all, --branches, --tags or --remotes"},
+	}
+
+	for _, expect := range table {
+		rev, err := ParseRev(expect.expr)
+		assert.Nil(rev, expect.expr)
+
+		if assert.IsType(&RevParseError{}, err, expect.expr) {
+			e := err.(*RevParseError)
+			assert.Equal(expect.expr, e.Expr)
+			assert.Equal(expect.offset, e.Offset, expect.expr)
+			assert.Equal(expect.reason, e.Reason, expect.expr)
+		}
+	}
+
+	_, err := ParseRev("HEAD~x")
+	assert.Equal("\"HEAD~x\" is invalid revision at 5: unexpected 'x'", err.Error())
+}
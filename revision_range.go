@@ -2,7 +2,10 @@ package gitlog
 
 import (
 	"fmt"
+	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -11,6 +14,21 @@ type RevArgs interface {
 	Args() []string
 }
 
+// RevValidator is implemented by RevArgs that can check itself before git-log is executed
+type RevValidator interface {
+	Validate() error
+}
+
+// RevConflictError is returned when RevArgs that can not be combined are used together
+type RevConflictError struct {
+	Revs   []string
+	Reason string
+}
+
+func (e *RevConflictError) Error() string {
+	return fmt.Sprintf("\"%s\" %s", strings.Join(e.Revs, "\", \""), e.Reason)
+}
+
 // Rev is useful for specifying refname
 type Rev struct {
 	Ref string
@@ -21,6 +39,11 @@ func (rev *Rev) Args() []string {
 	return []string{rev.Ref}
 }
 
+// Validate ...
+func (rev *Rev) Validate() error {
+	return ValidateRevision(rev.Ref)
+}
+
 // RevRange is useful for specifying refname range
 // alias for `<ref>..<ref>`
 type RevRange struct {
@@ -33,6 +56,208 @@ func (rev *RevRange) Args() []string {
 	return []string{fmt.Sprintf("%s..%s", rev.Old, rev.New)}
 }
 
+// Validate ...
+func (rev *RevRange) Validate() error {
+	return validateSides(rev.Args()[0], rev.Old, rev.New)
+}
+
+// validateSides checks both sides of range, either side can be omitted
+func validateSides(revision, left, right string) error {
+	if left == "" && right == "" {
+		return &InvalidRevError{
+			Rev:    revision,
+			Offset: 0,
+			Reason: "range requires at least one side",
+		}
+	}
+
+	for _, side := range []string{left, right} {
+		if side == "" {
+			continue
+		}
+		if err := ValidateRevision(side); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RevSymmetric is useful for specifying symmetric difference
+// alias for `--left-right <ref>...<ref>`
+type RevSymmetric struct {
+	Left       string
+	Right      string
+	CherryMark bool // alias for `--cherry-mark`
+	CherryPick bool // alias for `--cherry-pick`
+}
+
+// Args ...
+func (rev *RevSymmetric) Args() []string {
+	args := []string{"--left-right"}
+
+	if rev.CherryMark {
+		args = append(args, "--cherry-mark")
+	}
+
+	if rev.CherryPick {
+		args = append(args, "--cherry-pick")
+	}
+
+	return append(args, rev.revision())
+}
+
+// Validate ...
+func (rev *RevSymmetric) Validate() error {
+	if rev.CherryMark && rev.CherryPick {
+		return &RevConflictError{
+			Revs:   []string{"RevSymmetric"},
+			Reason: "can not use CherryMark and CherryPick together",
+		}
+	}
+	return validateSides(rev.revision(), rev.Left, rev.Right)
+}
+
+func (rev *RevSymmetric) revision() string {
+	return fmt.Sprintf("%s...%s", rev.Left, rev.Right)
+}
+
+func (rev *RevSymmetric) sideArgs() []string {
+	return []string{"--left-right", rev.revision()}
+}
+
+// revSides is implemented by RevArgs that split commits into the left and right sides
+type revSides interface {
+	sideArgs() []string
+}
+
+// RevSet is useful for specifying any number of refs to include and exclude
+// alias for `<ref>... ^<ref>...` with `--branches`, `--tags`, `--remotes`, `--exclude` and `--not`
+type RevSet struct {
+	Include  []string
+	Exclude  []string   // alias for `^<ref>`
+	Branches []string   // alias for `--branches[=<pattern>]`, empty pattern means all branches
+	Tags     []string   // alias for `--tags[=<pattern>]`, empty pattern means all tags
+	Remotes  []string   // alias for `--remotes[=<pattern>]`, empty pattern means all remotes
+	Ignore   []string   // alias for `--exclude=<pattern>`, repeated before each of Branches, Tags and Remotes
+	Globs    []*RefGlob // refs with their own excludes
+	Not      []*RefGlob // alias for `--not <glob>...`, commits reachable from the refs are excluded
+}
+
+// RefGlob selects refs by pattern
+// alias for `[--exclude=<pattern>...] --all|--branches|--tags|--remotes[=<pattern>]`
+type RefGlob struct {
+	Type    RefType  // zero value means all refs (`--all`)
+	Pattern string   // empty pattern means all refs of Type, can not be used with `--all`
+	Exclude []string // alias for `--exclude=<pattern>`, applied to this glob only
+}
+
+var refGlobOptions = map[RefType]string{
+	RefTypeBranch: "--branches",
+	RefTypeRemote: "--remotes",
+	RefTypeTag:    "--tags",
+}
+
+func (glob *RefGlob) args() []string {
+	args := []string{}
+
+	// `--exclude` is cleared by the next glob, so it is emitted right before the glob
+	for _, pattern := range glob.Exclude {
+		args = append(args, "--exclude="+pattern)
+	}
+
+	if glob.Type == 0 {
+		return append(args, "--all")
+	}
+
+	option := refGlobOptions[glob.Type]
+	if glob.Pattern != "" {
+		option += "=" + glob.Pattern
+	}
+
+	return append(args, option)
+}
+
+func (glob *RefGlob) validate() error {
+	if glob == nil {
+		return &RevConflictError{Revs: []string{"RefGlob"}, Reason: "must not be nil"}
+	}
+
+	if _, ok := refGlobOptions[glob.Type]; !ok && glob.Type != 0 {
+		return &RevConflictError{Revs: []string{"RefGlob"}, Reason: fmt.Sprintf("is unknown ref type (%d)", glob.Type)}
+	}
+
+	if glob.Type == 0 && glob.Pattern != "" {
+		return &RevConflictError{Revs: []string{"RefGlob"}, Reason: "pattern can not be used with --all"}
+	}
+
+	return nil
+}
+
+// Args ...
+func (rev *RevSet) Args() []string {
+	args := []string{}
+
+	patterns := []struct {
+		option   string
+		patterns []string
+	}{
+		{"--branches", rev.Branches},
+		{"--tags", rev.Tags},
+		{"--remotes", rev.Remotes},
+	}
+
+	// `--exclude` is cleared by the each pattern options, so it repeats
+	for _, p := range patterns {
+		for _, pattern := range p.patterns {
+			for _, ignore := range rev.Ignore {
+				args = append(args, "--exclude="+ignore)
+			}
+
+			if pattern == "" {
+				args = append(args, p.option)
+			} else {
+				args = append(args, p.option+"="+pattern)
+			}
+		}
+	}
+
+	for _, glob := range rev.Globs {
+		args = append(args, glob.args()...)
+	}
+
+	args = append(args, rev.Include...)
+
+	for _, ref := range rev.Exclude {
+		args = append(args, "^"+ref)
+	}
+
+	// `--not` flips the meaning of the following revisions, so it comes last
+	if len(rev.Not) > 0 {
+		args = append(args, "--not")
+
+		for _, glob := range rev.Not {
+			args = append(args, glob.args()...)
+		}
+	}
+
+	return args
+}
+
+// Validate ...
+func (rev *RevSet) Validate() error {
+	for _, glob := range append(append([]*RefGlob{}, rev.Globs...), rev.Not...) {
+		if err := glob.validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := validateRevisions(rev.Include); err != nil {
+		return err
+	}
+	return validateRevisions(rev.Exclude)
+}
+
 // RevAll alias for `--all`
 type RevAll struct{}
 
@@ -51,16 +276,51 @@ func (rev *RevNumber) Args() []string {
 	return []string{"-n", strconv.Itoa(rev.Limit)}
 }
 
+// Validate ...
+func (rev *RevNumber) Validate() error {
+	if rev.Limit < 0 {
+		return &RevConflictError{
+			Revs:   []string{"RevNumber"},
+			Reason: fmt.Sprintf("must not be negative (%d)", rev.Limit),
+		}
+	}
+	return nil
+}
+
+// RevSkip alias for `--skip <number>`
+type RevSkip struct {
+	Offset int
+}
+
+// Args ...
+func (rev *RevSkip) Args() []string {
+	return []string{"--skip", strconv.Itoa(rev.Offset)}
+}
+
+// Validate ...
+func (rev *RevSkip) Validate() error {
+	if rev.Offset < 0 {
+		return &RevConflictError{
+			Revs:   []string{"RevSkip"},
+			Reason: fmt.Sprintf("must not be negative (%d)", rev.Offset),
+		}
+	}
+	return nil
+}
+
 // RevTime alias for `--since <date> --until <date>`
+// dates are given with the UTC offset, so they do not depend on the timezone of git
 type RevTime struct {
 	Since time.Time
 	Until time.Time
 }
 
+const revTimeLayout = "2006-01-02 15:04:05 -0700"
+
 // Args ...
 func (rev *RevTime) Args() []string {
-	since := rev.Since.Format("2006-01-02 15:04:05")
-	until := rev.Until.Format("2006-01-02 15:04:05")
+	since := rev.Since.Format(revTimeLayout)
+	until := rev.Until.Format(revTimeLayout)
 
 	if !rev.Since.IsZero() && !rev.Until.IsZero() {
 		return []string{
@@ -75,3 +335,226 @@ func (rev *RevTime) Args() []string {
 
 	return []string{}
 }
+
+// timeNow is replaceable for testing
+var timeNow = time.Now
+
+// RevTimeLast returns RevTime since the given duration before now
+func RevTimeLast(d time.Duration) *RevTime {
+	return &RevTime{
+		Since: timeNow().Add(-d),
+	}
+}
+
+var relativeTimeRegex = regexp.MustCompile(`^(?:last\s+)?(?:(\d+)\s+)?(second|minute|hour|day|week|month|year)s?(?:\s+ago)?$`)
+
+// ParseRevTime parses relative expression such as "last 2 weeks" or "3 days ago" into RevTime
+// days, weeks, months and years are calendar based on the location of now
+func ParseRevTime(expr string) (*RevTime, error) {
+	res := relativeTimeRegex.FindStringSubmatch(strings.ToLower(strings.TrimSpace(expr)))
+	if res == nil {
+		return nil, fmt.Errorf("\"%s\" is not a relative time expression", expr)
+	}
+
+	n := 1
+	if res[1] != "" {
+		n, _ = strconv.Atoi(res[1])
+	}
+
+	now := timeNow()
+	var since time.Time
+
+	switch res[2] {
+	case "second":
+		since = now.Add(-time.Duration(n) * time.Second)
+	case "minute":
+		since = now.Add(-time.Duration(n) * time.Minute)
+	case "hour":
+		since = now.Add(-time.Duration(n) * time.Hour)
+	case "day":
+		since = now.AddDate(0, 0, -n)
+	case "week":
+		since = now.AddDate(0, 0, -n*7)
+	case "month":
+		since = addMonths(now, -n)
+	case "year":
+		since = addMonths(now, -n*12)
+	}
+
+	return &RevTime{
+		Since: since,
+	}, nil
+}
+
+// addMonths is AddDate clamped to the last day of the month, e.g. 1 month before Mar 31 is Feb 28
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+
+	// the day before the first day of the next month
+	last := first.AddDate(0, 1, -1).Day()
+
+	day := t.Day()
+	if day > last {
+		day = last
+	}
+
+	return first.AddDate(0, 0, day-1)
+}
+
+// RevGroup combines multiple RevArgs into one argument list
+type RevGroup struct {
+	Revs []RevArgs
+}
+
+// Args ...
+func (rev *RevGroup) Args() []string {
+	args := []string{}
+
+	for _, r := range rev.flatten() {
+		if !isNilRev(r) {
+			args = append(args, r.Args()...)
+		}
+	}
+
+	return args
+}
+
+// Validate check for RevArgs that conflict with each other
+func (rev *RevGroup) Validate() error {
+	var all, refs, sides, number, skip, since, until []string
+
+	for _, r := range rev.flatten() {
+		name := revName(r)
+
+		if isNilRev(r) {
+			return &RevConflictError{
+				Revs:   []string{name},
+				Reason: "must not be nil",
+			}
+		}
+
+		if v, ok := r.(RevValidator); ok {
+			if err := v.Validate(); err != nil {
+				return err
+			}
+		}
+
+		if s, ok := r.(revSides); ok && len(s.sideArgs()) > 0 {
+			sides = append(sides, name)
+		}
+
+		switch r := r.(type) {
+		case *RevAll:
+			all = append(all, name)
+		case *Rev, *RevRange, *RevSymmetric, *RevSet:
+			refs = append(refs, name)
+		case *RevNumber:
+			number = append(number, name)
+		case *RevSkip:
+			skip = append(skip, name)
+		case *RevTime:
+			if !r.Since.IsZero() {
+				since = append(since, name)
+			}
+			if !r.Until.IsZero() {
+				until = append(until, name)
+			}
+		default:
+			// RevArgs implemented outside of this package are classified by their arguments
+			for _, arg := range r.Args() {
+				switch {
+				case arg == "--all":
+					all = appendUnique(all, name)
+				case arg == "-n" || strings.HasPrefix(arg, "--max-count"):
+					number = appendUnique(number, name)
+				case strings.HasPrefix(arg, "--skip"):
+					skip = appendUnique(skip, name)
+				case strings.HasPrefix(arg, "--since") || strings.HasPrefix(arg, "--after"):
+					since = appendUnique(since, name)
+				case strings.HasPrefix(arg, "--until") || strings.HasPrefix(arg, "--before"):
+					until = appendUnique(until, name)
+				case !strings.HasPrefix(arg, "-"):
+					refs = appendUnique(refs, name)
+				}
+			}
+		}
+	}
+
+	// only one side of symmetric difference can be marked
+	if len(sides) > 1 {
+		return &RevConflictError{
+			Revs:   sides,
+			Reason: "can not be used together",
+		}
+	}
+
+	if len(all) > 0 && len(refs) > 0 {
+		return &RevConflictError{
+			Revs:   append([]string{all[0]}, refs...),
+			Reason: "can not be used together",
+		}
+	}
+
+	for _, names := range [][]string{number, skip} {
+		if len(names) > 1 {
+			return &RevConflictError{
+				Revs:   names,
+				Reason: "can not be specified more than once",
+			}
+		}
+	}
+
+	if len(since) > 1 {
+		return &RevConflictError{
+			Revs:   since,
+			Reason: "can not specify since more than once",
+		}
+	}
+
+	if len(until) > 1 {
+		return &RevConflictError{
+			Revs:   until,
+			Reason: "can not specify until more than once",
+		}
+	}
+
+	return nil
+}
+
+func (rev *RevGroup) sideArgs() []string {
+	for _, r := range rev.flatten() {
+		if sides, ok := r.(revSides); ok && !isNilRev(r) {
+			return sides.sideArgs()
+		}
+	}
+	return nil
+}
+
+// flatten expands the nested RevGroup and removes nil, typed nil such as `(*RevRange)(nil)` is kept for Validate
+func (rev *RevGroup) flatten() []RevArgs {
+	revs := []RevArgs{}
+
+	for _, r := range rev.Revs {
+		if r == nil {
+			continue
+		}
+
+		if group, ok := r.(*RevGroup); ok && group != nil {
+			revs = append(revs, group.flatten()...)
+		} else {
+			revs = append(revs, r)
+		}
+	}
+
+	return revs
+}
+
+// isNilRev returns true for typed nil pointer
+func isNilRev(rev RevArgs) bool {
+	v := reflect.ValueOf(rev)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+func revName(rev RevArgs) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", rev), "*gitlog.")
+}
@@ -37,6 +37,67 @@ func TestRevRange(t *testing.T) {
 	assert.Equal([]string{"v0.0.1..v0.1.2"}, rev.Args())
 }
 
+func TestRevSymmetric(t *testing.T) {
+	assert := assert.New(t)
+
+	rev := &RevSymmetric{
+		Left:  "master",
+		Right: "topic",
+	}
+	assert.Equal([]string{"--left-right", "master...topic"}, rev.Args())
+	assert.Nil(rev.Validate())
+
+	rev = &RevSymmetric{
+		Left:       "v1.0.0",
+		Right:      "v2.0.0",
+		CherryMark: true,
+	}
+	assert.Equal([]string{"--left-right", "--cherry-mark", "v1.0.0...v2.0.0"}, rev.Args())
+	assert.Equal([]string{"--left-right", "v1.0.0...v2.0.0"}, rev.sideArgs())
+
+	rev = &RevSymmetric{
+		Left:       "v1.0.0",
+		Right:      "v2.0.0",
+		CherryPick: true,
+	}
+	assert.Equal([]string{"--left-right", "--cherry-pick", "v1.0.0...v2.0.0"}, rev.Args())
+
+	rev.CherryMark = true
+	assert.IsType(&RevConflictError{}, rev.Validate())
+}
+
+func TestRevSet(t *testing.T) {
+	assert := assert.New(t)
+
+	rev := &RevSet{
+		Include: []string{"feature/a", "feature/b"},
+		Exclude: []string{"master", "v1.0.0"},
+	}
+	assert.Equal([]string{"feature/a", "feature/b", "^master", "^v1.0.0"}, rev.Args())
+
+	rev = &RevSet{
+		Branches: []string{"release/*"},
+		Exclude:  []string{"master"},
+	}
+	assert.Equal([]string{"--branches=release/*", "^master"}, rev.Args())
+
+	rev = &RevSet{
+		Branches: []string{""},
+		Tags:     []string{"v1.*", "v2.*"},
+		Remotes:  []string{"origin/*"},
+		Ignore:   []string{"*-rc*"},
+	}
+	assert.Equal([]string{
+		"--exclude=*-rc*", "--branches",
+		"--exclude=*-rc*", "--tags=v1.*",
+		"--exclude=*-rc*", "--tags=v2.*",
+		"--exclude=*-rc*", "--remotes=origin/*",
+	}, rev.Args())
+
+	rev = &RevSet{}
+	assert.Equal([]string{}, rev.Args())
+}
+
 func TestRevAll(t *testing.T) {
 	assert := assert.New(t)
 
@@ -51,12 +112,18 @@ func TestRevNumber(t *testing.T) {
 		Limit: 10,
 	}
 	assert.Equal([]string{"-n", "10"}, rev.Args())
+	assert.Nil(rev.Validate())
+
+	rev = &RevNumber{
+		Limit: -1,
+	}
+	assert.IsType(&RevConflictError{}, rev.Validate())
 }
 
 func TestRevTime(t *testing.T) {
 	assert := assert.New(t)
 	now := time.Now()
-	formattedNow := now.Format("2006-01-02 15:04:05")
+	formattedNow := now.Format("2006-01-02 15:04:05 -0700")
 
 	rev := &RevTime{
 		Since: now,
@@ -84,3 +151,215 @@ func TestRevTime(t *testing.T) {
 	rev = &RevTime{}
 	assert.Equal([]string{}, rev.Args())
 }
+
+func TestRevTimeZone(t *testing.T) {
+	assert := assert.New(t)
+
+	jst := time.FixedZone("JST", 9*60*60)
+	est := time.FixedZone("EST", -5*60*60)
+
+	rev := &RevTime{
+		Since: time.Date(2018, 1, 2, 9, 0, 0, 0, jst),
+		Until: time.Date(2018, 1, 2, 7, 0, 0, 0, est),
+	}
+	assert.Equal([]string{
+		"--since", "2018-01-02 09:00:00 +0900",
+		"--until", "2018-01-02 07:00:00 -0500",
+	}, rev.Args())
+
+	rev = &RevTime{
+		Since: time.Date(2018, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	assert.Equal([]string{"--since", "2018-01-02 00:00:00 +0000"}, rev.Args())
+}
+
+func TestRevTimeRelative(t *testing.T) {
+	assert := assert.New(t)
+
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2018, 3, 31, 12, 30, 0, 0, jst)
+
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	rev := RevTimeLast(36 * time.Hour)
+	assert.Equal(time.Date(2018, 3, 30, 0, 30, 0, 0, jst), rev.Since)
+	assert.True(rev.Until.IsZero())
+
+	table := map[string]time.Time{
+		"last 2 weeks":    time.Date(2018, 3, 17, 12, 30, 0, 0, jst),
+		"3 days ago":      time.Date(2018, 3, 28, 12, 30, 0, 0, jst),
+		"last week":       time.Date(2018, 3, 24, 12, 30, 0, 0, jst),
+		"1 month":         time.Date(2018, 2, 28, 12, 30, 0, 0, jst),
+		"13 months ago":   time.Date(2017, 2, 28, 12, 30, 0, 0, jst),
+		"2 years ago":     time.Date(2016, 3, 31, 12, 30, 0, 0, jst),
+		"Last 90 Minutes": time.Date(2018, 3, 31, 11, 0, 0, 0, jst),
+		"45 seconds ago":  time.Date(2018, 3, 31, 12, 29, 15, 0, jst),
+		" 6 hours ":       time.Date(2018, 3, 31, 6, 30, 0, 0, jst),
+	}
+
+	for expr, expect := range table {
+		rev, err := ParseRevTime(expr)
+		assert.Nil(err, expr)
+		assert.Equal(expect, rev.Since, expr)
+		assert.Equal([]string{"--since", expect.Format("2006-01-02 15:04:05 -0700")}, rev.Args(), expr)
+	}
+
+	// leap year
+	now = time.Date(2020, 3, 31, 12, 30, 0, 0, jst)
+	rev, _ = ParseRevTime("1 month")
+	assert.Equal(time.Date(2020, 2, 29, 12, 30, 0, 0, jst), rev.Since)
+
+	now = time.Date(2020, 2, 29, 12, 30, 0, 0, jst)
+	rev, _ = ParseRevTime("1 year")
+	assert.Equal(time.Date(2019, 2, 28, 12, 30, 0, 0, jst), rev.Since)
+
+	now = time.Date(2018, 1, 15, 12, 30, 0, 0, jst)
+	rev, _ = ParseRevTime("2 months")
+	assert.Equal(time.Date(2017, 11, 15, 12, 30, 0, 0, jst), rev.Since)
+
+	for _, expr := range []string{"", "yesterday", "2 fortnights", "last -1 days", "weeks 2"} {
+		rev, err := ParseRevTime(expr)
+		assert.Nil(rev, expr)
+		assert.NotNil(err, expr)
+	}
+}
+
+func TestRevSkip(t *testing.T) {
+	assert := assert.New(t)
+
+	rev := &RevSkip{
+		Offset: 20,
+	}
+	assert.Equal([]string{"--skip", "20"}, rev.Args())
+	assert.Nil(rev.Validate())
+
+	rev = &RevSkip{
+		Offset: -1,
+	}
+	assert.IsType(&RevConflictError{}, rev.Validate())
+}
+
+func TestRevGroup(t *testing.T) {
+	assert := assert.New(t)
+	since := time.Date(2018, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	rev := &RevGroup{
+		Revs: []RevArgs{
+			&RevRange{Old: "v1", New: "v2"},
+			&RevTime{Since: since},
+			nil,
+			&RevGroup{
+				Revs: []RevArgs{
+					&RevNumber{Limit: 50},
+					&RevSkip{Offset: 100},
+				},
+			},
+		},
+	}
+
+	args := rev.Args()
+	assert.Equal("v1..v2", args[0])
+	assert.Equal("--since", args[1])
+	assert.Equal([]string{"-n", "50", "--skip", "100"}, args[3:])
+	assert.Nil(rev.Validate())
+
+	rev = &RevGroup{}
+	assert.Equal([]string{}, rev.Args())
+	assert.Nil(rev.Validate())
+
+	// typed nil does not panic
+	rev = &RevGroup{Revs: []RevArgs{(*RevRange)(nil), (*RevGroup)(nil), &RevNumber{1}}}
+	assert.Equal([]string{"-n", "1"}, rev.Args())
+	assert.Nil(rev.sideArgs())
+}
+
+// customRev is RevArgs implemented outside of RevGroup
+type customRev []string
+
+func (rev *customRev) Args() []string {
+	return *rev
+}
+
+func TestRevGroupConflict(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+
+	table := []struct {
+		revs   []RevArgs
+		names  []string
+		reason string
+	}{
+		{
+			[]RevArgs{&RevNumber{1}, &RevGroup{Revs: []RevArgs{&RevNumber{2}}}},
+			[]string{"RevNumber", "RevNumber"},
+			"can not be specified more than once",
+		},
+		{
+			[]RevArgs{&RevSkip{1}, &RevSkip{2}},
+			[]string{"RevSkip", "RevSkip"},
+			"can not be specified more than once",
+		},
+		{
+			[]RevArgs{&RevAll{}, &Rev{"master"}, &RevRange{Old: "v1", New: "v2"}},
+			[]string{"RevAll", "Rev", "RevRange"},
+			"can not be used together",
+		},
+		{
+			[]RevArgs{&RevSet{Include: []string{"master"}}, &RevAll{}},
+			[]string{"RevAll", "RevSet"},
+			"can not be used together",
+		},
+		{
+			[]RevArgs{&RevTime{Since: now}, &RevTime{Since: now, Until: now}},
+			[]string{"RevTime", "RevTime"},
+			"can not specify since more than once",
+		},
+		{
+			[]RevArgs{&RevTime{Until: now}, &RevTime{Since: now, Until: now}},
+			[]string{"RevTime", "RevTime"},
+			"can not specify until more than once",
+		},
+		{
+			[]RevArgs{&RevNumber{1}, &RevSkip{-1}},
+			[]string{"RevSkip"},
+			"must not be negative (-1)",
+		},
+		{
+			[]RevArgs{&RevNumber{1}, (*RevRange)(nil)},
+			[]string{"RevRange"},
+			"must not be nil",
+		},
+		{
+			[]RevArgs{&RevGroup{Revs: []RevArgs{(*RevGroup)(nil)}}},
+			[]string{"RevGroup"},
+			"must not be nil",
+		},
+		{
+			[]RevArgs{&RevSymmetric{Left: "a", Right: "b"}, &RevSymmetric{Left: "c", Right: "d"}},
+			[]string{"RevSymmetric", "RevSymmetric"},
+			"can not be used together",
+		},
+		{
+			[]RevArgs{&RevAll{}, &customRev{"master"}},
+			[]string{"RevAll", "customRev"},
+			"can not be used together",
+		},
+		{
+			[]RevArgs{&customRev{"-n", "1"}, &RevNumber{2}},
+			[]string{"customRev", "RevNumber"},
+			"can not be specified more than once",
+		},
+	}
+
+	for _, expect := range table {
+		err := (&RevGroup{Revs: expect.revs}).Validate()
+		assert.IsType(&RevConflictError{}, err)
+		assert.Equal(expect.names, err.(*RevConflictError).Revs)
+		assert.Equal(expect.reason, err.(*RevConflictError).Reason)
+	}
+
+	assert.Nil((&RevGroup{
+		Revs: []RevArgs{&RevTime{Since: now}, &RevTime{Until: now}},
+	}).Validate())
+}
@@ -0,0 +1,331 @@
+package gitlog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RefError is returned when refs can not be resolved to an object ID
+type RefError struct {
+	Missing   []string
+	Ambiguous []string
+}
+
+func (e *RefError) Error() string {
+	messages := []string{}
+
+	if len(e.Missing) > 0 {
+		messages = append(messages, fmt.Sprintf("unknown revision \"%s\"", strings.Join(e.Missing, "\", \"")))
+	}
+
+	if len(e.Ambiguous) > 0 {
+		messages = append(messages, fmt.Sprintf("ambiguous revision \"%s\"", strings.Join(e.Ambiguous, "\", \"")))
+	}
+
+	return strings.Join(messages, ", ")
+}
+
+// ResolvedRev is RevArgs whose refs are pinned to object IDs
+// repeated git-log with ResolvedRev stays on the same snapshot
+type ResolvedRev struct {
+	Rev  RevArgs           // RevArgs with the refs replaced by object IDs
+	Refs map[string]string // map of ref to object ID
+}
+
+// Args ...
+func (rev *ResolvedRev) Args() []string {
+	return rev.Rev.Args()
+}
+
+// Validate ...
+func (rev *ResolvedRev) Validate() error {
+	if validator, ok := rev.Rev.(RevValidator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+func (rev *ResolvedRev) sideArgs() []string {
+	if sides, ok := rev.Rev.(revSides); ok {
+		return sides.sideArgs()
+	}
+	return nil
+}
+
+// revResolvable is implemented by RevArgs that specify the starting points by refs
+type revResolvable interface {
+	refs() []string
+	pin(map[string]string) RevArgs
+}
+
+// startsFromHead returns true if rev is the built-in RevArgs without starting points, so git-log starts from HEAD
+func startsFromHead(rev RevArgs) bool {
+	switch rev := rev.(type) {
+	case nil, *RevNumber, *RevSkip, *RevTime:
+		return true
+	case *RevGroup:
+		if rev == nil {
+			return true
+		}
+		for _, r := range rev.flatten() {
+			if !startsFromHead(r) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// revRefs returns refs used by rev, git-log starts from HEAD if rev has no starting points
+// the custom RevArgs has no refs, since its starting points are unknown
+func revRefs(rev RevArgs) []string {
+	if r, ok := rev.(revResolvable); ok {
+		return r.refs()
+	}
+	if startsFromHead(rev) {
+		return []string{"HEAD"}
+	}
+	return nil
+}
+
+// pinRev replaces the refs of rev with object IDs, the custom RevArgs is passed through unpinned
+func pinRev(rev RevArgs, ids map[string]string) RevArgs {
+	if r, ok := rev.(revResolvable); ok {
+		return r.pin(ids)
+	}
+	if !startsFromHead(rev) {
+		return rev
+	}
+
+	head := &Rev{Ref: ids["HEAD"]}
+	if rev == nil {
+		return head
+	}
+
+	return &RevGroup{Revs: []RevArgs{rev, head}}
+}
+
+func orHead(ref string) string {
+	if ref == "" {
+		return "HEAD"
+	}
+	return ref
+}
+
+func (rev *Rev) refs() []string {
+	return []string{orHead(rev.Ref)}
+}
+
+func (rev *Rev) pin(ids map[string]string) RevArgs {
+	return &Rev{Ref: ids[orHead(rev.Ref)]}
+}
+
+func (rev *RevRange) refs() []string {
+	return []string{orHead(rev.Old), orHead(rev.New)}
+}
+
+func (rev *RevRange) pin(ids map[string]string) RevArgs {
+	return &RevRange{
+		Old: ids[orHead(rev.Old)],
+		New: ids[orHead(rev.New)],
+	}
+}
+
+func (rev *RevSymmetric) refs() []string {
+	return []string{orHead(rev.Left), orHead(rev.Right)}
+}
+
+func (rev *RevSymmetric) pin(ids map[string]string) RevArgs {
+	return &RevSymmetric{
+		Left:       ids[orHead(rev.Left)],
+		Right:      ids[orHead(rev.Right)],
+		CherryMark: rev.CherryMark,
+		CherryPick: rev.CherryPick,
+	}
+}
+
+// refs of RevSet does not include the patterns, they are not pinned
+func (rev *RevSet) refs() []string {
+	return append(append([]string{}, rev.Include...), rev.Exclude...)
+}
+
+func (rev *RevSet) pin(ids map[string]string) RevArgs {
+	pinned := *rev
+	pinned.Include = pinRefs(rev.Include, ids)
+	pinned.Exclude = pinRefs(rev.Exclude, ids)
+	return &pinned
+}
+
+func (*RevAll) refs() []string {
+	return nil
+}
+
+func (rev *RevAll) pin(ids map[string]string) RevArgs {
+	return rev
+}
+
+func (rev *RevGroup) refs() []string {
+	refs := []string{}
+	starts := false
+
+	for _, r := range rev.flatten() {
+		if resolvable, ok := r.(revResolvable); ok {
+			refs = append(refs, resolvable.refs()...)
+		}
+		starts = starts || !startsFromHead(r)
+	}
+
+	if !starts {
+		refs = append(refs, "HEAD")
+	}
+
+	return refs
+}
+
+func (rev *RevGroup) pin(ids map[string]string) RevArgs {
+	revs := []RevArgs{}
+	starts := false
+
+	for _, r := range rev.flatten() {
+		if resolvable, ok := r.(revResolvable); ok {
+			revs = append(revs, resolvable.pin(ids))
+		} else {
+			revs = append(revs, r)
+		}
+		starts = starts || !startsFromHead(r)
+	}
+
+	if !starts {
+		revs = append(revs, &Rev{Ref: ids["HEAD"]})
+	}
+
+	return &RevGroup{Revs: revs}
+}
+
+func (rev *ResolvedRev) refs() []string {
+	return revRefs(rev.Rev)
+}
+
+func (rev *ResolvedRev) pin(ids map[string]string) RevArgs {
+	return rev.Rev
+}
+
+func pinRefs(refs []string, ids map[string]string) []string {
+	if refs == nil {
+		return nil
+	}
+
+	pinned := make([]string, len(refs))
+	for i, ref := range refs {
+		pinned[i] = ids[ref]
+	}
+
+	return pinned
+}
+
+// Resolve resolves every ref in rev to an object ID
+// only the refs of the built-in RevArgs are verified and pinned, the custom RevArgs is passed to git-log as it is
+func (gitLog *Client) Resolve(rev RevArgs) (*ResolvedRev, error) {
+	if err := gitLog.prepare(); err != nil {
+		return nil, err
+	}
+
+	return gitLog.resolve(rev)
+}
+
+func (gitLog *Client) resolve(rev RevArgs) (*ResolvedRev, error) {
+	if validator, ok := rev.(RevValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := map[string]string{}
+	refErr := &RefError{}
+
+	for _, ref := range revRefs(rev) {
+		if _, ok := ids[ref]; ok {
+			continue
+		}
+
+		id, err := gitLog.exec("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+		if err != nil || id == "" {
+			if gitLog.isAmbiguousObject(ref) {
+				refErr.Ambiguous = append(refErr.Ambiguous, ref)
+			} else {
+				refErr.Missing = append(refErr.Missing, ref)
+			}
+			continue
+		}
+
+		if gitLog.isAmbiguousRef(ref) {
+			refErr.Ambiguous = append(refErr.Ambiguous, ref)
+			continue
+		}
+
+		ids[ref] = id
+	}
+
+	if len(refErr.Missing) > 0 || len(refErr.Ambiguous) > 0 {
+		return nil, refErr
+	}
+
+	return &ResolvedRev{
+		Rev:  pinRev(rev, ids),
+		Refs: ids,
+	}, nil
+}
+
+var (
+	refNameRegex   = regexp.MustCompile(`^[^~^:@]+`)
+	hexPrefixRegex = regexp.MustCompile(`^[0-9a-fA-F]{4,39}$`)
+)
+
+// isAmbiguousRef checks whether the refname part of ref matches multiple refs
+func (gitLog *Client) isAmbiguousRef(ref string) bool {
+	name := refNameRegex.FindString(ref)
+	if name == "" || name == "HEAD" {
+		return false
+	}
+
+	candidates := []string{
+		name,
+		"refs/" + name,
+		"refs/tags/" + name,
+		"refs/heads/" + name,
+		"refs/remotes/" + name,
+		"refs/remotes/" + name + "/HEAD",
+	}
+
+	out, err := gitLog.exec("for-each-ref", append([]string{"--format=%(refname)"}, candidates...)...)
+	if err != nil {
+		return false
+	}
+
+	count := 0
+	for _, line := range strings.Split(out, "\n") {
+		for _, candidate := range candidates {
+			if line == candidate {
+				count++
+			}
+		}
+	}
+
+	return count > 1
+}
+
+// isAmbiguousObject checks whether the abbreviated object name matches multiple objects
+func (gitLog *Client) isAmbiguousObject(ref string) bool {
+	if !hexPrefixRegex.MatchString(ref) {
+		return false
+	}
+
+	out, err := gitLog.exec("rev-parse", "--disambiguate="+ref)
+	if err != nil {
+		return false
+	}
+
+	return len(strings.Fields(out)) > 1
+}
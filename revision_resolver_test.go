@@ -0,0 +1,143 @@
+package gitlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPinRev(t *testing.T) {
+	assert := assert.New(t)
+
+	ids := map[string]string{
+		"HEAD":   "aaaa",
+		"master": "bbbb",
+		"v1.0.0": "cccc",
+		"topic":  "dddd",
+	}
+
+	table := []struct {
+		rev    RevArgs
+		refs   []string
+		expect RevArgs
+	}{
+		{nil, []string{"HEAD"}, &Rev{Ref: "aaaa"}},
+		{&Rev{Ref: "master"}, []string{"master"}, &Rev{Ref: "bbbb"}},
+		{&RevRange{Old: "v1.0.0"}, []string{"v1.0.0", "HEAD"}, &RevRange{Old: "cccc", New: "aaaa"}},
+		{
+			&RevSymmetric{Left: "master", Right: "topic", CherryMark: true},
+			[]string{"master", "topic"},
+			&RevSymmetric{Left: "bbbb", Right: "dddd", CherryMark: true},
+		},
+		{
+			&RevSet{Include: []string{"topic"}, Exclude: []string{"master"}, Branches: []string{"release/*"}},
+			[]string{"topic", "master"},
+			&RevSet{Include: []string{"dddd"}, Exclude: []string{"bbbb"}, Branches: []string{"release/*"}},
+		},
+		{&RevAll{}, nil, &RevAll{}},
+		{
+			&RevNumber{Limit: 5},
+			[]string{"HEAD"},
+			&RevGroup{Revs: []RevArgs{&RevNumber{Limit: 5}, &Rev{Ref: "aaaa"}}},
+		},
+		{
+			&RevGroup{Revs: []RevArgs{&RevNumber{Limit: 5}, &RevSkip{Offset: 5}}},
+			[]string{"HEAD"},
+			&RevGroup{Revs: []RevArgs{&RevNumber{Limit: 5}, &RevSkip{Offset: 5}, &Rev{Ref: "aaaa"}}},
+		},
+		{
+			&RevGroup{Revs: []RevArgs{&RevRange{Old: "v1.0.0", New: "master"}, &RevNumber{Limit: 5}}},
+			[]string{"v1.0.0", "master"},
+			&RevGroup{Revs: []RevArgs{&RevRange{Old: "cccc", New: "bbbb"}, &RevNumber{Limit: 5}}},
+		},
+		{&customRev{"topic"}, nil, &customRev{"topic"}},
+		{
+			&RevGroup{Revs: []RevArgs{&customRev{"topic"}, &RevNumber{Limit: 5}}},
+			[]string{},
+			&RevGroup{Revs: []RevArgs{&customRev{"topic"}, &RevNumber{Limit: 5}}},
+		},
+	}
+
+	for _, expect := range table {
+		assert.Equal(expect.refs, revRefs(expect.rev))
+		assert.Equal(expect.expect, pinRev(expect.rev, ids))
+	}
+}
+
+func TestResolve(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	resolved, err := gitLog.Resolve(&RevRange{Old: "v1.0.0"})
+	assert.Nil(err)
+	assert.Equal(2, len(resolved.Refs))
+	assert.Len(resolved.Refs["v1.0.0"], 40)
+	assert.Len(resolved.Refs["HEAD"], 40)
+	assert.Equal([]string{resolved.Refs["v1.0.0"] + ".." + resolved.Refs["HEAD"]}, resolved.Args())
+
+	commits, err := gitLog.Log(resolved, nil)
+	assert.Nil(err)
+	assert.Equal(6, len(commits))
+
+	// stays on the same snapshot
+	git("-C", ".tmp", "commit", "--allow-empty", "-m", "feat(*): New commit")
+
+	commits, err = gitLog.Log(resolved, nil)
+	assert.Nil(err)
+	assert.Equal(6, len(commits))
+
+	commits, err = gitLog.Log(&RevRange{Old: "v1.0.0"}, nil)
+	assert.Nil(err)
+	assert.Equal(7, len(commits))
+}
+
+func TestResolveError(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	git("-C", ".tmp", "branch", "2.1.0", "v1.0.0")
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	resolved, err := gitLog.Resolve(&RevSet{
+		Include: []string{"master", "notfound", "2.1.0", "HEAD~1"},
+		Exclude: []string{"v0.0.0", "HEAD~100"},
+	})
+
+	assert.Nil(resolved)
+	assert.IsType(&RefError{}, err)
+	assert.Equal([]string{"notfound", "v0.0.0", "HEAD~100"}, err.(*RefError).Missing)
+	assert.Equal([]string{"2.1.0"}, err.(*RefError).Ambiguous)
+	assert.Equal("unknown revision \"notfound\", \"v0.0.0\", \"HEAD~100\", ambiguous revision \"2.1.0\"", err.Error())
+
+	commits, err := gitLog.Log(&RevRange{
+		Old: "v0.0.0",
+		New: "master",
+	}, &Params{
+		Verify: true,
+	})
+
+	assert.Nil(commits)
+	assert.IsType(&RefError{}, err)
+	assert.Equal([]string{"v0.0.0"}, err.(*RefError).Missing)
+
+	commits, err = gitLog.Log(&RevRange{
+		Old: "v1.0.0",
+		New: "master",
+	}, &Params{
+		Verify: true,
+	})
+
+	assert.Nil(err)
+	assert.Equal(6, len(commits))
+}
@@ -0,0 +1,142 @@
+package gitlog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InvalidRevError is returned when the revision can not be passed to git safely
+type InvalidRevError struct {
+	Rev    string
+	Offset int // byte offset in Rev
+	Reason string
+}
+
+func (e *InvalidRevError) Error() string {
+	return fmt.Sprintf("\"%s\" is invalid revision at %d: %s", e.Rev, e.Offset, e.Reason)
+}
+
+// ValidateRevision checks the revision against git's refname rules
+// suffixes such as `~<n>`, `^<n>`, `^{<type>}` and `@{<date>}` are allowed,
+// option-like revisions (e.g. `--output=/tmp/x`) are rejected
+// `^!`, `^@` and `^-<n>` are not supported since they stand for several revisions, use RevRange or RevSet instead
+func ValidateRevision(rev string) error {
+	if offset, reason := checkRevision(rev); reason != "" {
+		return &InvalidRevError{
+			Rev:    rev,
+			Offset: offset,
+			Reason: reason,
+		}
+	}
+	return nil
+}
+
+func validateRevisions(revs []string) error {
+	for _, rev := range revs {
+		if err := ValidateRevision(rev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRevision returns the offset and the reason of the first problem, the reason is empty if rev is valid
+func checkRevision(rev string) (int, string) {
+	if rev == "" {
+		return 0, "empty revision"
+	}
+
+	if rev[0] == '-' {
+		return 0, "option-like revision is not allowed"
+	}
+
+	i := 0
+
+	// refname or object name
+	for i < len(rev) {
+		c := rev[i]
+		if c == '~' || c == '^' || c == ':' || (c == '@' && i+1 < len(rev) && rev[i+1] == '{') {
+			break
+		}
+
+		switch {
+		case c < 0x20 || c == 0x7f:
+			return i, "unexpected control character"
+		case c == ' ' || c == '?' || c == '*' || c == '[' || c == '\\':
+			return i, fmt.Sprintf("unexpected %q", c)
+		case c == '.' && (i == 0 || rev[i-1] == '.' || rev[i-1] == '/'):
+			return i, "unexpected '.'"
+		case c == '/' && (i == 0 || rev[i-1] == '/'):
+			return i, "unexpected '/'"
+		}
+
+		i++
+	}
+
+	name := rev[:i]
+
+	if name == "" && !strings.HasPrefix(rev, "@{") {
+		return 0, fmt.Sprintf("missing ref before %q", rev[0])
+	}
+
+	if strings.HasSuffix(name, "/") || strings.HasSuffix(name, ".") {
+		return i - 1, fmt.Sprintf("unexpected %q", name[i-1])
+	}
+
+	begin := 0
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasSuffix(component, ".lock") {
+			return begin + len(component) - len(".lock"), "unexpected '.lock'"
+		}
+		begin += len(component) + 1
+	}
+
+	// suffixes
+	for i < len(rev) {
+		switch rev[i] {
+		case '~':
+			i = skipDigits(rev, i+1)
+
+		case '^':
+			if i+1 < len(rev) && rev[i+1] == '{' {
+				end := strings.IndexByte(rev[i+1:], '}')
+				if end < 0 {
+					return i + 1, "unclosed '^{'"
+				}
+				i += end + 2
+			} else if i+1 < len(rev) && (rev[i+1] == '!' || rev[i+1] == '@' || rev[i+1] == '-') {
+				return i, fmt.Sprintf("'^%c' is not supported", rev[i+1])
+			} else {
+				i = skipDigits(rev, i+1)
+			}
+
+		case '@':
+			if i+1 >= len(rev) || rev[i+1] != '{' {
+				return i, "unexpected '@'"
+			}
+			end := strings.IndexByte(rev[i+1:], '}')
+			if end < 0 {
+				return i + 1, "unclosed '@{'"
+			}
+			if end == 1 {
+				return i + 1, "empty '@{}'"
+			}
+			i += end + 2
+
+		case ':':
+			return i, "path and search syntax are not supported"
+
+		default:
+			return i, fmt.Sprintf("unexpected %q", rev[i])
+		}
+	}
+
+	return -1, ""
+}
+
+func skipDigits(rev string, i int) int {
+	for i < len(rev) && rev[i] >= '0' && rev[i] <= '9' {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,169 @@
+package gitlog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRevision(t *testing.T) {
+	assert := assert.New(t)
+
+	valid := []string{
+		"HEAD",
+		"@",
+		"master",
+		"feature/foo-bar_baz",
+		"refs/heads/release/1.x",
+		"v1.0.0",
+		"3.6.4-beta.12",
+		"5e312d5",
+		"HEAD~5",
+		"HEAD^",
+		"master^2~3",
+		"v1.0.0^{commit}",
+		"v1.0.0^{}",
+		"@{2.weeks.ago}",
+		"master@{upstream}",
+		"foo@bar",
+	}
+
+	for _, rev := range valid {
+		assert.Nil(ValidateRevision(rev), rev)
+	}
+
+	table := []struct {
+		rev    string
+		offset int
+		reason string
+	}{
+		{"", 0, "empty revision"},
+		{"--output=/tmp/x", 0, "option-like revision is not allowed"},
+		{"--exec=rm -rf /", 0, "option-like revision is not allowed"},
+		{"-n", 0, "option-like revision is not allowed"},
+		{"-", 0, "option-like revision is not allowed"},
+		{"master -p", 6, "unexpected ' '"},
+		{"master\n--output=x", 6, "unexpected control character"},
+		{"foo\x00", 3, "unexpected control character"},
+		{"foo*", 3, "unexpected '*'"},
+		{"foo?", 3, "unexpected '?'"},
+		{"foo[", 3, "unexpected '['"},
+		{"foo\\bar", 3, "unexpected '\\\\'"},
+		{".hidden", 0, "unexpected '.'"},
+		{"refs/.hidden", 5, "unexpected '.'"},
+		{"a..b", 2, "unexpected '.'"},
+		{"/foo", 0, "unexpected '/'"},
+		{"foo//bar", 4, "unexpected '/'"},
+		{"foo/", 3, "unexpected '/'"},
+		{"foo.", 3, "unexpected '.'"},
+		{"foo.lock", 3, "unexpected '.lock'"},
+		{"refs/heads/foo.lock/bar", 14, "unexpected '.lock'"},
+		{"^foo", 0, "missing ref before '^'"},
+		{"HEAD~1x", 6, "unexpected 'x'"},
+		{"HEAD:--output", 4, "path and search syntax are not supported"},
+		{"HEAD^{commit", 5, "unclosed '^{'"},
+		{"HEAD@{", 5, "unclosed '@{'"},
+		{"HEAD^!", 4, "'^!' is not supported"},
+		{"HEAD^@", 4, "'^@' is not supported"},
+		{"HEAD^-2", 4, "'^-' is not supported"},
+	}
+
+	for _, expect := range table {
+		err := ValidateRevision(expect.rev)
+
+		if assert.IsType(&InvalidRevError{}, err, expect.rev) {
+			e := err.(*InvalidRevError)
+			assert.Equal(expect.rev, e.Rev)
+			assert.Equal(expect.offset, e.Offset, expect.rev)
+			assert.Equal(expect.reason, e.Reason, expect.rev)
+		}
+	}
+
+	err := ValidateRevision("--output=/tmp/x")
+	assert.Equal("\"--output=/tmp/x\" is invalid revision at 0: option-like revision is not allowed", err.Error())
+}
+
+func TestRevValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	valid := []RevValidator{
+		&Rev{Ref: "master"},
+		&RevRange{Old: "v1.0.0", New: "HEAD"},
+		&RevRange{Old: "v1.0.0"},
+		&RevRange{New: "v1.0.0"},
+		&RevSymmetric{Left: "a", Right: "b"},
+		&RevSet{Include: []string{"a"}, Exclude: []string{"b"}, Branches: []string{"release/*"}},
+		&RevGroup{Revs: []RevArgs{&Rev{Ref: "master"}, &RevNumber{Limit: 1}}},
+	}
+
+	for _, rev := range valid {
+		assert.Nil(rev.Validate())
+	}
+
+	invalid := []RevValidator{
+		&Rev{Ref: "--output=/tmp/x"},
+		&Rev{},
+		&RevRange{Old: "--exec=sh", New: "master"},
+		&RevRange{Old: "master", New: "--all"},
+		&RevRange{},
+		&RevSymmetric{Left: "-p", Right: "master"},
+		&RevSymmetric{},
+		&RevSet{Include: []string{"master", "--output=x"}},
+		&RevSet{Exclude: []string{"--all"}},
+		&RevGroup{Revs: []RevArgs{&RevNumber{Limit: 1}, &Rev{Ref: "--output=x"}}},
+	}
+
+	for _, rev := range invalid {
+		assert.IsType(&InvalidRevError{}, rev.Validate())
+	}
+}
+
+func TestGitLogOptionInjection(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	dir, _ := ioutil.TempDir("", "gitlog")
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "output")
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	table := []RevArgs{
+		&Rev{Ref: "--output=" + output},
+		&RevRange{Old: "--output=" + output, New: "master"},
+		&RevSymmetric{Left: "master", Right: "--output=" + output},
+		&RevSet{Include: []string{"--output=" + output}},
+		&RevGroup{Revs: []RevArgs{&Rev{Ref: "--output=" + output}}},
+	}
+
+	for _, rev := range table {
+		commits, err := gitLog.Log(rev, nil)
+		assert.Nil(commits)
+		assert.IsType(&InvalidRevError{}, err)
+
+		resolved, err := gitLog.Resolve(rev)
+		assert.Nil(resolved)
+		assert.IsType(&InvalidRevError{}, err)
+	}
+
+	rev, err := gitLog.SinceRef("--output=" + output)
+	assert.Nil(rev)
+	assert.IsType(&InvalidRevError{}, err)
+
+	_, err = os.Stat(output)
+	assert.True(os.IsNotExist(err))
+
+	// a ref that looks like a path
+	ioutil.WriteFile(filepath.Join(".tmp", "v1.0.0"), []byte("file"), 0644)
+
+	commits, err := gitLog.Log(&Rev{Ref: "v1.0.0"}, nil)
+	assert.Nil(err)
+	assert.Equal(1, len(commits))
+}
@@ -0,0 +1,117 @@
+package gitlog
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is semantic version
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string
+	Build      string
+}
+
+var versionRegex = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`)
+
+// ParseVersion parses semantic version such as `v1.0.0`, `2.1.0` or `v3.0.0-rc.10`
+func ParseVersion(str string) (*Version, error) {
+	res := versionRegex.FindStringSubmatch(str)
+	if res == nil {
+		return nil, fmt.Errorf("\"%s\" is not semantic version", str)
+	}
+
+	version := &Version{
+		Build: res[5],
+	}
+
+	for i, n := range []*int{&version.Major, &version.Minor, &version.Patch} {
+		v, err := strconv.Atoi(res[i+1])
+		if err != nil {
+			return nil, err
+		}
+		*n = v
+	}
+
+	if res[4] != "" {
+		version.Prerelease = strings.Split(res[4], ".")
+	}
+
+	return version, nil
+}
+
+// IsPrerelease returns true if version has prerelease identifiers
+func (v *Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Compare returns -1, 0 or +1 by the precedence of semantic version, build metadata is ignored
+func (v *Version) Compare(other *Version) int {
+	for _, pair := range [][2]int{
+		{v.Major, other.Major},
+		{v.Minor, other.Minor},
+		{v.Patch, other.Patch},
+	} {
+		if c := compareInt(pair[0], pair[1]); c != 0 {
+			return c
+		}
+	}
+
+	// a version without prerelease has higher precedence
+	if !v.IsPrerelease() || !other.IsPrerelease() {
+		return -compareInt(len(v.Prerelease), len(other.Prerelease))
+	}
+
+	for i := 0; i < len(v.Prerelease) && i < len(other.Prerelease); i++ {
+		if c := comparePrerelease(v.Prerelease[i], other.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+
+	return compareInt(len(v.Prerelease), len(other.Prerelease))
+}
+
+func (v *Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+
+	if v.IsPrerelease() {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+
+	return s
+}
+
+// comparePrerelease compares numeric identifiers numerically, and others in ASCII order
+func comparePrerelease(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+
+	switch {
+	case errA == nil && errB == nil:
+		return compareInt(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
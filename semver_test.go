@@ -0,0 +1,80 @@
+package gitlog
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	table := map[string]*Version{
+		"v1.0.0":           {1, 0, 0, nil, ""},
+		"2.1.0":            {2, 1, 0, nil, ""},
+		"v3.0.0-rc.10":     {3, 0, 0, []string{"rc", "10"}, ""},
+		"3.6.4-beta.12":    {3, 6, 4, []string{"beta", "12"}, ""},
+		"1.2.3-alpha+b.42": {1, 2, 3, []string{"alpha"}, "b.42"},
+		"10.20.30+exp":     {10, 20, 30, nil, "exp"},
+	}
+
+	for str, expect := range table {
+		version, err := ParseVersion(str)
+		assert.Nil(err, str)
+		assert.Equal(expect, version, str)
+	}
+
+	for _, str := range []string{"", "latest", "v1", "1.0", "01.0.0", "1.0.0-", "1.0.0-rc..1", "V1.0.0", "v1.0.0.0"} {
+		version, err := ParseVersion(str)
+		assert.Nil(version, str)
+		assert.NotNil(err, str)
+	}
+
+	version, _ := ParseVersion("v3.0.0-rc.10+build.1")
+	assert.Equal("3.0.0-rc.10+build.1", version.String())
+	assert.True(version.IsPrerelease())
+}
+
+func TestVersionCompare(t *testing.T) {
+	assert := assert.New(t)
+
+	// in ascending order
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"v1.0.0",
+		"2.1.0",
+		"v3.0.0-rc.9",
+		"v3.0.0-rc.10",
+		"3.0.0",
+		"3.6.4-beta.12",
+		"3.10.0",
+	}
+
+	versions := []*Version{}
+	for _, str := range ordered {
+		version, _ := ParseVersion(str)
+		versions = append(versions, version)
+	}
+
+	for i := range versions {
+		for j := range versions {
+			expect := compareInt(i, j)
+			assert.Equal(expect, versions[i].Compare(versions[j]), "%s <=> %s", ordered[i], ordered[j])
+		}
+	}
+
+	shuffled := []*Version{versions[5], versions[13], versions[0], versions[10], versions[9], versions[7]}
+	sort.Slice(shuffled, func(i, j int) bool { return shuffled[i].Compare(shuffled[j]) < 0 })
+	assert.Equal([]*Version{versions[0], versions[5], versions[7], versions[9], versions[10], versions[13]}, shuffled)
+
+	a, _ := ParseVersion("1.0.0+a")
+	b, _ := ParseVersion("v1.0.0+b")
+	assert.Equal(0, a.Compare(b))
+}
@@ -0,0 +1,208 @@
+package gitlog
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const tagsFormat = separator +
+	"%(refname)" + delimiter +
+	"%(objecttype)" + delimiter +
+	"%(objectname) %(objectname:short)" + delimiter +
+	"%(*objectname) %(*objectname:short)" + delimiter +
+	"%(taggername)" + delimiter +
+	"%(taggeremail)" + delimiter +
+	"%(taggerdate:unix)" + delimiter +
+	"%(authordate:unix)" + delimiter +
+	"%(contents)" + delimiter +
+	"%(contents:signature)"
+
+// TagSort is the order of tags
+type TagSort int
+
+// List of TagSort
+const (
+	TagSortVersion TagSort = iota // semantic version, tags that are not semantic version come first
+	TagSortName
+	TagSortDate
+)
+
+// TagFilter filters tags by the kind of version
+type TagFilter int
+
+// List of TagFilter
+const (
+	TagFilterAll        TagFilter = iota
+	TagFilterStable               // semantic version without prerelease
+	TagFilterPrerelease           // semantic version with prerelease
+)
+
+// TagsParams for getting tags
+type TagsParams struct {
+	Pattern string // glob pattern of tag name (e.g. `v1.*`)
+	Merged  string // only tags reachable from the ref (`--merged <ref>`)
+	Filter  TagFilter
+	Sort    TagSort
+	Reverse bool
+}
+
+// Tags returns the tags of the repository sorted by semantic version in ascending order
+func (gitLog *Client) Tags(params *TagsParams) ([]*Tag, error) {
+	if params == nil {
+		params = &TagsParams{}
+	}
+
+	// Check pattern before running git
+	if _, err := path.Match(params.Pattern, ""); err != nil {
+		return nil, err
+	}
+
+	if params.Merged != "" {
+		if err := ValidateRevision(params.Merged); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := gitLog.prepare(); err != nil {
+		return nil, err
+	}
+
+	return gitLog.tags(params)
+}
+
+func (gitLog *Client) tags(params *TagsParams) ([]*Tag, error) {
+	args := []string{"--format=" + tagsFormat}
+
+	if params.Merged != "" {
+		args = append(args, "--merged="+params.Merged)
+	}
+
+	out, err := gitLog.exec("for-each-ref", append(args, "refs/tags")...)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []*Tag{}
+
+	for _, record := range strings.Split(out, separator)[1:] {
+		tag := parseTagRecord(record)
+
+		if params.Pattern != "" {
+			if ok, _ := path.Match(params.Pattern, tag.Name); !ok {
+				continue
+			}
+		}
+
+		switch params.Filter {
+		case TagFilterStable:
+			if tag.Version == nil || tag.Version.IsPrerelease() {
+				continue
+			}
+		case TagFilterPrerelease:
+			if tag.Version == nil || !tag.Version.IsPrerelease() {
+				continue
+			}
+		}
+
+		tags = append(tags, tag)
+	}
+
+	sortTags(tags, params.Sort)
+
+	if params.Reverse {
+		for i, j := 0, len(tags)-1; i < j; i, j = i+1, j-1 {
+			tags[i], tags[j] = tags[j], tags[i]
+		}
+	}
+
+	return tags, nil
+}
+
+func parseTagRecord(record string) *Tag {
+	fields := strings.Split(record, delimiter)
+	for len(fields) < 10 {
+		fields = append(fields, "")
+	}
+
+	tag := &Tag{
+		Name: strings.TrimPrefix(fields[0], "refs/tags/"),
+	}
+
+	if fields[1] == "tag" {
+		tag.Type = TagAnnotated
+		tag.Commit = parseTagHash(fields[3])
+		tag.Tagger = &Tagger{
+			Name:  fields[4],
+			Email: strings.Trim(fields[5], "<>"),
+			Date:  parseUnix(fields[6]),
+		}
+		tag.Date = tag.Tagger.Date
+		// signed tags have the PGP signature at the end of contents
+		contents := fields[8]
+		if signature := strings.TrimSpace(fields[9]); signature != "" {
+			if i := strings.LastIndex(contents, signature); i >= 0 {
+				contents = contents[:i]
+			}
+		}
+		tag.Message = strings.TrimSpace(contents)
+	} else {
+		tag.Type = TagLightweight
+		tag.Commit = parseTagHash(fields[2])
+		tag.Date = parseUnix(fields[7])
+	}
+
+	if version, err := ParseVersion(tag.Name); err == nil {
+		tag.Version = version
+	}
+
+	return tag
+}
+
+func parseTagHash(str string) *Hash {
+	parts := strings.Fields(str)
+	if len(parts) < 2 {
+		return nil
+	}
+
+	return &Hash{
+		Long:  parts[0],
+		Short: parts[1],
+	}
+}
+
+func parseUnix(str string) time.Time {
+	timestamp, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(timestamp, 0)
+}
+
+func sortTags(tags []*Tag, by TagSort) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		a, b := tags[i], tags[j]
+
+		switch by {
+		case TagSortVersion:
+			if a.Version == nil || b.Version == nil {
+				if a.Version != nil || b.Version != nil {
+					return a.Version == nil
+				}
+				break
+			}
+			if c := a.Version.Compare(b.Version); c != 0 {
+				return c < 0
+			}
+
+		case TagSortDate:
+			if !a.Date.Equal(b.Date) {
+				return a.Date.Before(b.Date)
+			}
+		}
+
+		return a.Name < b.Name
+	})
+}
@@ -0,0 +1,142 @@
+package gitlog
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTags() func() {
+	clear := setup()
+
+	git("-C", ".tmp", "tag", "-a", "v3.0.0-rc.9", "-m", "Release candidate 9", "2.1.0")
+	git("-C", ".tmp", "tag", "-a", "v3.0.0", "-m", "Release v3.0.0\n\nBreaking changes", "v3.0.0-rc.10")
+	git("-C", ".tmp", "tag", "latest")
+
+	return clear
+}
+
+func tagNames(tags []*Tag) []string {
+	names := []string{}
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+func TestTags(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setupTags()
+	defer clear()
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	tags, err := gitLog.Tags(nil)
+	assert.Nil(err)
+	assert.Equal([]string{
+		"latest",
+		"v1.0.0",
+		"2.1.0",
+		"v3.0.0-rc.9",
+		"v3.0.0-rc.10",
+		"v3.0.0",
+		"3.6.4-beta.12",
+	}, tagNames(tags))
+
+	commits, _ := gitLog.Log(&Rev{Ref: "v3.0.0-rc.10"}, nil)
+
+	lightweight := tags[4]
+	assert.Equal(TagLightweight, lightweight.Type)
+	assert.Equal(commits[0].Hash, lightweight.Commit)
+	assert.Equal(commits[0].Author.Date, lightweight.Date)
+	assert.Nil(lightweight.Tagger)
+	assert.Equal("", lightweight.Message)
+	assert.Equal(&Version{3, 0, 0, []string{"rc", "10"}, ""}, lightweight.Version)
+
+	annotated := tags[5]
+	assert.Equal(TagAnnotated, annotated.Type)
+	assert.Equal(commits[0].Hash, annotated.Commit)
+	assert.Equal("authorname", annotated.Tagger.Name)
+	assert.Equal("mail@example.com", annotated.Tagger.Email)
+	assert.False(annotated.Tagger.Date.IsZero())
+	assert.Equal(annotated.Tagger.Date, annotated.Date)
+	assert.Equal("Release v3.0.0\n\nBreaking changes", annotated.Message)
+
+	assert.Nil(tags[0].Version)
+}
+
+func TestTagsSigned(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setupTags()
+	defer clear()
+
+	// signed tag made without gpg
+	content := git("-C", ".tmp", "cat-file", "tag", "v3.0.0")
+	content = strings.Replace(content, "tag v3.0.0", "tag v3.0.1", 1) +
+		"-----BEGIN PGP SIGNATURE-----\n\niQEzBAABCAAdFiEE\n-----END PGP SIGNATURE-----\n"
+
+	cmd := exec.Command("git", "-C", ".tmp", "mktag")
+	cmd.Stdin = strings.NewReader(content)
+	out, err := cmd.Output()
+	assert.Nil(err)
+	git("-C", ".tmp", "update-ref", "refs/tags/v3.0.1", strings.TrimSpace(string(out)))
+
+	tags, err := NewClient(&Config{Path: ".tmp"}).Tags(&TagsParams{Pattern: "v3.0.1"})
+	assert.Nil(err)
+	assert.Equal(1, len(tags))
+	assert.Equal(TagAnnotated, tags[0].Type)
+	assert.Equal("Release v3.0.0\n\nBreaking changes", tags[0].Message)
+}
+
+func TestTagsParams(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setupTags()
+	defer clear()
+
+	gitLog := NewClient(&Config{
+		Path: ".tmp",
+	})
+
+	table := []struct {
+		params *TagsParams
+		expect []string
+	}{
+		{
+			&TagsParams{Filter: TagFilterStable},
+			[]string{"v1.0.0", "2.1.0", "v3.0.0"},
+		},
+		{
+			&TagsParams{Filter: TagFilterPrerelease, Reverse: true},
+			[]string{"3.6.4-beta.12", "v3.0.0-rc.10", "v3.0.0-rc.9"},
+		},
+		{
+			&TagsParams{Pattern: "v3.*"},
+			[]string{"v3.0.0-rc.9", "v3.0.0-rc.10", "v3.0.0"},
+		},
+		{
+			&TagsParams{Pattern: "v*", Filter: TagFilterStable, Reverse: true},
+			[]string{"v3.0.0", "v1.0.0"},
+		},
+		{
+			&TagsParams{Sort: TagSortName},
+			[]string{"2.1.0", "3.6.4-beta.12", "latest", "v1.0.0", "v3.0.0", "v3.0.0-rc.10", "v3.0.0-rc.9"},
+		},
+	}
+
+	for _, expect := range table {
+		tags, err := gitLog.Tags(expect.params)
+		assert.Nil(err)
+		assert.Equal(expect.expect, tagNames(tags))
+	}
+
+	tags, err := gitLog.Tags(&TagsParams{Pattern: "["})
+	assert.Nil(tags)
+	assert.NotNil(err)
+}
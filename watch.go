@@ -0,0 +1,313 @@
+package gitlog
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EventType of the watch event
+type EventType int
+
+// List of EventType
+const (
+	EventCommit        EventType = iota + 1 // new commit on a branch
+	EventBranchCreated                      // branch is created
+	EventBranchDeleted                      // branch is deleted
+	EventBranchForced                       // branch is updated to a commit which is not a descendant (e.g. force push)
+	EventTagCreated                         // tag is created
+	EventTagDeleted                         // tag is deleted
+)
+
+var eventTypeNames = map[EventType]string{
+	EventCommit:        "commit",
+	EventBranchCreated: "branch-created",
+	EventBranchDeleted: "branch-deleted",
+	EventBranchForced:  "branch-forced",
+	EventTagCreated:    "tag-created",
+	EventTagDeleted:    "tag-deleted",
+}
+
+func (typ EventType) String() string {
+	return eventTypeNames[typ]
+}
+
+// Event emitted by Watcher
+type Event struct {
+	Type   EventType
+	Ref    *Ref    // ref before the change for deleted events, otherwise after the change
+	Old    string  // object ID before the change, empty if created
+	New    string  // object ID after the change, empty if deleted
+	Commit *Commit // new commit, only for EventCommit
+}
+
+// WatchParams for watching the repository
+type WatchParams struct {
+	Interval time.Duration // polling interval, default 2s
+	Remotes  bool          // watch remote-tracking branches too
+}
+
+// Watcher polls the refs of the repository and emits Event
+// both Events and Errors must be received until they are closed.
+type Watcher struct {
+	Events <-chan *Event
+	Errors <-chan error
+
+	gitLog *Client
+	types  []RefType
+	refs   map[string]*Ref
+	events chan *Event
+	errors chan error
+}
+
+// Watch starts watching the repository, the changes after Watch are emitted
+// Events and Errors are closed when ctx is done.
+func Watch(ctx context.Context, config *Config, params *WatchParams) (*Watcher, error) {
+	interval := 2 * time.Second
+	types := []RefType{RefTypeBranch, RefTypeTag}
+
+	if params != nil {
+		if params.Interval < 0 {
+			return nil, &ParamsError{
+				Options: []string{"Interval"},
+				Reason:  "must not be negative",
+			}
+		}
+
+		if params.Interval > 0 {
+			interval = params.Interval
+		}
+
+		if params.Remotes {
+			types = append(types, RefTypeRemote)
+		}
+	}
+
+	events := make(chan *Event)
+	errors := make(chan error)
+
+	watcher := &Watcher{
+		Events: events,
+		Errors: errors,
+		gitLog: NewClient(config),
+		types:  types,
+		events: events,
+		errors: errors,
+	}
+
+	refs, err := watcher.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	watcher.refs = refs
+
+	go watcher.run(ctx, interval)
+
+	return watcher, nil
+}
+
+func (watcher *Watcher) run(ctx context.Context, interval time.Duration) {
+	defer close(watcher.events)
+	defer close(watcher.errors)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := watcher.poll(ctx); err != nil {
+			select {
+			case watcher.errors <- err:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (watcher *Watcher) snapshot() (map[string]*Ref, error) {
+	list, err := watcher.gitLog.Refs(&RefsParams{Types: watcher.types})
+	if err != nil {
+		return nil, err
+	}
+
+	refs := map[string]*Ref{}
+	for _, ref := range list {
+		refs[ref.Ref] = ref
+	}
+
+	return refs, nil
+}
+
+// poll compares the refs with the previous snapshot and emits the events
+func (watcher *Watcher) poll(ctx context.Context) error {
+	refs, err := watcher.snapshot()
+	if err != nil {
+		return err
+	}
+
+	events, err := watcher.diff(watcher.refs, refs)
+	if err != nil {
+		return err
+	}
+
+	// the snapshot is updated only when the events are computed,
+	// so the changes are retried on the next poll
+	watcher.refs = refs
+
+	for _, event := range events {
+		select {
+		case watcher.events <- event:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// diff returns the events between two snapshots sorted by refname
+func (watcher *Watcher) diff(prev, next map[string]*Ref) ([]*Event, error) {
+	names := []string{}
+	for name := range prev {
+		names = append(names, name)
+	}
+	for name := range next {
+		if _, ok := prev[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	events := []*Event{}
+
+	for _, name := range names {
+		before, after := prev[name], next[name]
+
+		switch {
+		case after == nil && before.Type == RefTypeTag:
+			events = append(events, &Event{Type: EventTagDeleted, Ref: before, Old: before.Commit.Long})
+		case after == nil:
+			events = append(events, &Event{Type: EventBranchDeleted, Ref: before, Old: before.Commit.Long})
+		case before == nil && after.Type == RefTypeTag:
+			events = append(events, &Event{Type: EventTagCreated, Ref: after, New: after.Commit.Long})
+		case before != nil && before.Commit.Long == after.Commit.Long:
+			continue
+		case before != nil && after.Type == RefTypeTag:
+			// moved tag
+			events = append(events,
+				&Event{Type: EventTagDeleted, Ref: before, Old: before.Commit.Long},
+				&Event{Type: EventTagCreated, Ref: after, New: after.Commit.Long},
+			)
+		default:
+			branchEvents, err := watcher.branchEvents(prev, before, after)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, branchEvents...)
+		}
+	}
+
+	return events, nil
+}
+
+// branchEvents returns the events of created or updated branch, followed by the new commits in chronological order
+// the old tip which no longer exists (e.g. gc after force push) is treated as forced update
+func (watcher *Watcher) branchEvents(prev map[string]*Ref, before, after *Ref) ([]*Event, error) {
+	events := []*Event{}
+	rev := &RevSet{Include: []string{after.Commit.Long}}
+
+	tips := []string{}
+	for _, ref := range prev {
+		if ref.Type != RefTypeTag {
+			tips = appendUnique(tips, ref.Commit.Long)
+		}
+	}
+
+	existing, err := watcher.existing(tips)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case before == nil:
+		events = append(events, &Event{Type: EventBranchCreated, Ref: after, New: after.Commit.Long})
+
+	case !existing[before.Commit.Long]:
+		events = append(events, &Event{Type: EventBranchForced, Ref: after, Old: before.Commit.Long, New: after.Commit.Long})
+
+	default:
+		ok, err := watcher.gitLog.IsAncestor(before.Commit.Long, after.Commit.Long)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			events = append(events, &Event{Type: EventBranchForced, Ref: after, Old: before.Commit.Long, New: after.Commit.Long})
+		}
+
+		// only the commits since the old tip
+		tips = []string{before.Commit.Long}
+	}
+
+	// commits not on the other branches
+	for _, tip := range tips {
+		if existing[tip] {
+			rev.Exclude = appendUnique(rev.Exclude, tip)
+		}
+	}
+
+	commits, err := watcher.gitLog.Log(rev, &Params{Reverse: true})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, commit := range commits {
+		events = append(events, &Event{
+			Type:   EventCommit,
+			Ref:    after,
+			Old:    commitID(before),
+			New:    after.Commit.Long,
+			Commit: commit,
+		})
+	}
+
+	return events, nil
+}
+
+// existing returns the set of ids whose commits exist in the repository
+func (watcher *Watcher) existing(ids []string) (map[string]bool, error) {
+	existing := map[string]bool{}
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	out, err := watcher.gitLog.execRaw(revsReader(ids), "cat-file", "--batch-check=%(objectname) %(objecttype)")
+	if err != nil {
+		return nil, err
+	}
+
+	// missing objects are `<id> missing`
+	for _, line := range splitLines(out) {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == "commit" {
+			existing[fields[0]] = true
+		}
+	}
+
+	return existing, nil
+}
+
+// commitID returns empty string if ref is nil
+func commitID(ref *Ref) string {
+	if ref == nil {
+		return ""
+	}
+	return ref.Commit.Long
+}
@@ -0,0 +1,168 @@
+package gitlog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type eventSummary struct {
+	Type    EventType
+	Name    string
+	Subject string
+}
+
+func summarize(events []*Event) []eventSummary {
+	list := []eventSummary{}
+	for _, event := range events {
+		summary := eventSummary{Type: event.Type, Name: event.Ref.Name}
+		if event.Commit != nil {
+			summary.Subject = event.Commit.Subject
+		}
+		list = append(list, summary)
+	}
+	return list
+}
+
+func TestWatchDiff(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watcher, err := Watch(ctx, &Config{Path: ".tmp"}, &WatchParams{Interval: time.Hour})
+	assert.Nil(err)
+
+	changes := func() []*Event {
+		refs, err := watcher.snapshot()
+		assert.Nil(err)
+
+		events, err := watcher.diff(watcher.refs, refs)
+		assert.Nil(err)
+
+		watcher.refs = refs
+		return events
+	}
+
+	assert.Empty(changes())
+
+	// new commits
+	gitCommit := func(msg string) {
+		git("-C", ".tmp", "commit", "--allow-empty", "-m", msg)
+	}
+	gitCommit("feat(a): Add a")
+	gitCommit("feat(b): Add b")
+	git("-C", ".tmp", "tag", "v4.0.0")
+	git("-C", ".tmp", "tag", "-d", "v1.0.0")
+
+	events := changes()
+	assert.Equal([]eventSummary{
+		{EventCommit, "master", "feat(a): Add a"},
+		{EventCommit, "master", "feat(b): Add b"},
+		{EventTagDeleted, "v1.0.0", ""},
+		{EventTagCreated, "v4.0.0", ""},
+	}, summarize(events))
+	assert.NotEmpty(events[0].Old)
+	assert.Equal(events[1].Commit.Hash.Long, events[0].New)
+	assert.Equal(events[1].Commit.Hash.Long, events[3].New)
+	assert.Equal("", events[2].New)
+
+	// created and deleted branches
+	git("-C", ".tmp", "checkout", "-b", "feature")
+	gitCommit("feat(c): Add c")
+	git("-C", ".tmp", "checkout", "master")
+	git("-C", ".tmp", "branch", "-D", "topic")
+
+	assert.Equal([]eventSummary{
+		{EventBranchCreated, "feature", ""},
+		{EventCommit, "feature", "feat(c): Add c"},
+		{EventBranchDeleted, "topic", ""},
+	}, summarize(changes()))
+
+	// force push
+	before := git("-C", ".tmp", "rev-parse", "master")
+	git("-C", ".tmp", "reset", "--hard", "HEAD~2")
+	gitCommit("feat(d): Add d")
+
+	events = changes()
+	assert.Equal([]eventSummary{
+		{EventBranchForced, "master", ""},
+		{EventCommit, "master", "feat(d): Add d"},
+	}, summarize(events))
+	assert.Equal(before[:40], events[0].Old)
+	assert.Equal(events[1].Commit.Hash.Long, events[0].New)
+
+	// the old tip is removed by gc
+	git("-C", ".tmp", "tag", "-d", "v4.0.0")
+	gitCommit("feat(e): Add e")
+	assert.Equal([]eventSummary{
+		{EventCommit, "master", "feat(e): Add e"},
+		{EventTagDeleted, "v4.0.0", ""},
+	}, summarize(changes()))
+
+	before = git("-C", ".tmp", "rev-parse", "master")
+	git("-C", ".tmp", "reset", "--hard", "HEAD~1")
+	gitCommit("feat(f): Add f")
+	git("-C", ".tmp", "reflog", "expire", "--expire=now", "--all")
+	git("-C", ".tmp", "gc", "--prune=now", "--quiet")
+
+	events = changes()
+	assert.Equal(eventSummary{EventBranchForced, "master", ""}, summarize(events)[0])
+	assert.Equal(before[:40], events[0].Old)
+	assert.Equal(eventSummary{EventCommit, "master", "feat(f): Add f"}, summarize(events)[len(events)-1])
+	assert.Empty(changes())
+
+	// moved tag
+	git("-C", ".tmp", "tag", "v4.0.0", "HEAD~1")
+	changes()
+	git("-C", ".tmp", "tag", "-f", "v4.0.0", "HEAD")
+
+	assert.Equal([]eventSummary{
+		{EventTagDeleted, "v4.0.0", ""},
+		{EventTagCreated, "v4.0.0", ""},
+	}, summarize(changes()))
+}
+
+func TestWatch(t *testing.T) {
+	assert := assert.New(t)
+
+	clear := setup()
+	defer clear()
+
+	_, err := Watch(context.Background(), &Config{Path: ".tmp"}, &WatchParams{Interval: -1})
+	assert.IsType(&ParamsError{}, err)
+
+	_, err = Watch(context.Background(), &Config{Path: ".tmp/missing"}, nil)
+	assert.IsType(&NotRepositoryError{}, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	watcher, err := Watch(ctx, &Config{Path: ".tmp"}, &WatchParams{Interval: 10 * time.Millisecond})
+	assert.Nil(err)
+
+	git("-C", ".tmp", "commit", "--allow-empty", "-m", "feat(watch): Add watcher")
+
+	select {
+	case event := <-watcher.Events:
+		assert.Equal(EventCommit, event.Type)
+		assert.Equal("commit", event.Type.String())
+		assert.Equal("feat(watch): Add watcher", event.Commit.Subject)
+	case err := <-watcher.Errors:
+		assert.Fail(err.Error())
+	case <-time.After(5 * time.Second):
+		assert.Fail("timeout")
+	}
+
+	cancel()
+
+	// channels are closed
+	for range watcher.Events {
+	}
+	for range watcher.Errors {
+	}
+}